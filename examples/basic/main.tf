@@ -37,7 +37,7 @@ resource "clarity_resource" "dev" {
 
   deployment {
     trigger {
-      manual_user_interface = false
+      type = "always"
     }
   }
 }
@@ -0,0 +1,80 @@
+package internal
+
+// awsPartition lists the regions available within an AWS partition.
+//
+// Keep this table in sync with
+// https://docs.aws.amazon.com/general/latest/gr/rande.html when AWS launches
+// new regions.
+type awsPartition struct {
+	ID      string
+	Regions []string
+}
+
+var awsPartitions = []awsPartition{
+	{
+		ID: "aws",
+		Regions: []string{
+			"af-south-1",
+			"ap-east-1",
+			"ap-east-2",
+			"ap-northeast-1",
+			"ap-northeast-2",
+			"ap-northeast-3",
+			"ap-south-1",
+			"ap-south-2",
+			"ap-southeast-1",
+			"ap-southeast-2",
+			"ap-southeast-3",
+			"ap-southeast-4",
+			"ap-southeast-5",
+			"ap-southeast-6",
+			"ap-southeast-7",
+			"ca-central-1",
+			"ca-west-1",
+			"eu-central-1",
+			"eu-central-2",
+			"eu-north-1",
+			"eu-south-1",
+			"eu-south-2",
+			"eu-west-1",
+			"eu-west-2",
+			"eu-west-3",
+			"il-central-1",
+			"me-central-1",
+			"me-south-1",
+			"mx-central-1",
+			"sa-east-1",
+			"us-east-1",
+			"us-east-2",
+			"us-west-1",
+			"us-west-2",
+		},
+	},
+	{
+		ID: "aws-us-gov",
+		Regions: []string{
+			"us-gov-east-1",
+			"us-gov-west-1",
+		},
+	},
+	{
+		ID: "aws-cn",
+		Regions: []string{
+			"cn-north-1",
+			"cn-northwest-1",
+		},
+	},
+}
+
+// awsPartitionForRegion returns the partition a region belongs to, or an
+// empty string if the region is not known.
+func awsPartitionForRegion(region string) string {
+	for _, p := range awsPartitions {
+		for _, r := range p.Regions {
+			if r == region {
+				return p.ID
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// deleteResourceWhenIdle deletes a resource, retrying while a deployment is in
+// progress until the timeout expires.
+func deleteResourceWhenIdle(ctx context.Context, api *clarity.Client, serviceSlug string, resourceSlug string, timeout time.Duration) error {
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		err := api.DeleteResource(serviceSlug, resourceSlug)
+		if errors.Is(err, clarity.ErrDeploymentInProgress) {
+			tflog.Debug(ctx, "waiting for deployment to finish before deleting resource", map[string]interface{}{
+				"service":  serviceSlug,
+				"resource": resourceSlug,
+			})
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+}
+
+// deleteServiceCascade deletes every resource attached to the service and then
+// the service itself.
+func deleteServiceCascade(ctx context.Context, api *clarity.Client, service clarity.Service, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, r := range service.Resources {
+		if err := deleteResourceWhenIdle(ctx, api, service.Slug, r.Slug, time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
+	return api.DeleteService(service.Slug)
+}
+
+// deleteProviderDependents deletes the services using the provider as their
+// repository provider, and any resources deployed through the provider.
+func deleteProviderDependents(ctx context.Context, api *clarity.Client, providerSlug string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	rsp, err := api.ListServices()
+	if err != nil {
+		return err
+	}
+
+	for _, service := range rsp.Services {
+		if service.Provider != nil && service.Provider.Slug == providerSlug {
+			if err := deleteServiceCascade(ctx, api, service, time.Until(deadline)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, r := range service.Resources {
+			if r.Provider != providerSlug {
+				continue
+			}
+			if err := deleteResourceWhenIdle(ctx, api, service.Slug, r.Slug, time.Until(deadline)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
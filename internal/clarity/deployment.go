@@ -0,0 +1,111 @@
+package clarity
+
+const (
+	StageAdvanceAutomatic = "automatic"
+	StageAdvanceManual    = "manual"
+
+	TriggerTypeAlways   = "always"
+	TriggerTypeEvent    = "event"
+	TriggerTypeSchedule = "schedule"
+
+	EventManual = "manual"
+	EventBranch = "branch"
+	EventTag    = "tag"
+
+	HealthCheckTypeAlarm = "alarm"
+	HealthCheckTypeHTTP  = "http"
+
+	MetricErrorRate  = "error_rate"  // percentage of failed invocations
+	MetricLatencyP99 = "latency_p99" // milliseconds
+	MetricThrottles  = "throttles"   // count
+
+	OperatorGreaterThan        = "gt"
+	OperatorGreaterThanOrEqual = "gte"
+	OperatorLessThan           = "lt"
+	OperatorLessThanOrEqual    = "lte"
+)
+
+var EvaluationMetrics = []string{
+	MetricErrorRate,
+	MetricLatencyP99,
+	MetricThrottles,
+}
+
+var EvaluationOperators = []string{
+	OperatorGreaterThan,
+	OperatorGreaterThanOrEqual,
+	OperatorLessThan,
+	OperatorLessThanOrEqual,
+}
+
+type UpdateDeploymentStrategy struct {
+	Strategy DeploymentStrategy `json:"strategy"`
+}
+
+type DeploymentStrategy struct {
+	Trigger    []DeploymentRule  `json:"trigger"`
+	Health     []HealthCheck     `json:"health"`
+	Evaluation []EvaluationRule  `json:"evaluation"`
+	Stages     []DeploymentStage `json:"stages"`
+}
+
+// ManualUserInterfaceTrigger reports whether any rule requires deployments to
+// be released manually through the user interface.
+func (x DeploymentStrategy) ManualUserInterfaceTrigger() bool {
+	for _, rule := range x.Trigger {
+		if rule.IsManual() {
+			return true
+		}
+	}
+	return false
+}
+
+// DeploymentRule triggers a deployment. Rules are evaluated in order.
+type DeploymentRule struct {
+	Type     string              `json:"type"`
+	Event    string              `json:"name,omitempty"`
+	Pattern  string              `json:"pattern,omitempty"` // branch or tag glob
+	Schedule *DeploymentSchedule `json:"schedule,omitempty"`
+}
+
+func (x DeploymentRule) IsManual() bool {
+	return x.Type == TriggerTypeEvent && x.Event == EventManual
+}
+
+// DeploymentSchedule queues deployments until the next open window.
+type DeploymentSchedule struct {
+	TimeZone string             `json:"time_zone"` // IANA time zone, e.g. "Europe/London"
+	Windows  []DeploymentWindow `json:"windows"`
+}
+
+type DeploymentWindow struct {
+	Days  string `json:"days"`  // cron day of week field, e.g. "TUE-THU"
+	Start string `json:"start"` // "HH:MM"
+	End   string `json:"end"`   // "HH:MM"
+}
+
+// HealthCheck gates a deployment, a failing check halts and rolls back the
+// deployment.
+type HealthCheck struct {
+	Type   string `json:"type"` // "alarm" or "http"
+	Alarm  string `json:"alarm,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Period int    `json:"period"` // seconds
+}
+
+// EvaluationRule compares a metric of the new version against a threshold.
+type EvaluationRule struct {
+	Metric    string  `json:"metric"`
+	Operator  string  `json:"operator"`
+	Threshold float64 `json:"threshold"`
+	// Compare against the metric prior to the deployment rather than an
+	// absolute value.
+	Baseline bool `json:"baseline"`
+}
+
+// DeploymentStage shifts a percentage of traffic to the new version.
+type DeploymentStage struct {
+	Traffic  int    `json:"traffic"`   // percentage
+	BakeTime int    `json:"bake_time"` // seconds
+	Advance  string `json:"advance"`   // "automatic" or "manual"
+}
@@ -0,0 +1,61 @@
+package clarity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var strategySnapshot = `
+{
+  "trigger": [{"type": "event", "name": "manual"}],
+  "health": [{"type": "alarm", "alarm": "api-errors", "period": 300}],
+  "evaluation": [{"metric": "error_rate", "operator": "gt", "threshold": 1.5, "baseline": true}],
+  "stages": [
+    {"traffic": 10, "bake_time": 600, "advance": "automatic"},
+    {"traffic": 100, "bake_time": 0, "advance": "manual"}
+  ]
+}
+`
+
+func TestDeploymentStrategySerialization(t *testing.T) {
+	var strategy DeploymentStrategy
+	err := json.Unmarshal([]byte(strategySnapshot), &strategy)
+	require.NoError(t, err)
+
+	require.True(t, strategy.ManualUserInterfaceTrigger())
+	require.Equal(t, []HealthCheck{
+		{Type: HealthCheckTypeAlarm, Alarm: "api-errors", Period: 300},
+	}, strategy.Health)
+	require.Equal(t, []EvaluationRule{
+		{Metric: "error_rate", Operator: "gt", Threshold: 1.5, Baseline: true},
+	}, strategy.Evaluation)
+	require.Equal(t, []DeploymentStage{
+		{Traffic: 10, BakeTime: 600, Advance: StageAdvanceAutomatic},
+		{Traffic: 100, BakeTime: 0, Advance: StageAdvanceManual},
+	}, strategy.Stages)
+
+	out, err := json.Marshal(strategy)
+	require.NoError(t, err)
+	require.JSONEq(t, strategySnapshot, string(out))
+}
+
+func TestUserInterfaceTrigger(t *testing.T) {
+	schedule := DeploymentRule{
+		Type:     TriggerTypeSchedule,
+		Schedule: &DeploymentSchedule{TimeZone: "UTC"},
+	}
+	branch := DeploymentRule{Type: TriggerTypeEvent, Event: EventBranch, Pattern: "main"}
+
+	empty := InternalResource{}
+	require.False(t, empty.ManualUserInterfaceTrigger())
+
+	x := InternalResource{Deployment: DeploymentStrategy{
+		Trigger: []DeploymentRule{{Type: TriggerTypeAlways}, schedule, branch},
+	}}
+	require.False(t, x.ManualUserInterfaceTrigger())
+
+	x.Deployment.Trigger = append(x.Deployment.Trigger, DeploymentRule{Type: TriggerTypeEvent, Event: EventManual})
+	require.True(t, x.ManualUserInterfaceTrigger())
+}
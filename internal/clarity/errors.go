@@ -3,3 +3,10 @@ package clarity
 import "errors"
 
 var ErrNotFound = errors.New("not found")
+
+var ErrDeploymentInProgress = errors.New("Unable to delete resource while there is an active deployment in progress")
+
+// ErrUnavailable is returned when a request carrying an idempotency key failed
+// without a definitive answer from the server, it is safe to repeat with the
+// same key.
+var ErrUnavailable = errors.New("Clarity API unavailable")
@@ -0,0 +1,145 @@
+package clarity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// FreezeRequest blocks deployments to the given services and resources
+// between Start and End, both RFC3339 timestamps.
+type FreezeRequest struct {
+	Start         string           `json:"start"`
+	End           string           `json:"end"`
+	Reason        string           `json:"reason"`
+	Services      []string         `json:"services"`
+	Resources     []FreezeResource `json:"resources"`
+	OverrideUsers []string         `json:"override_users"`
+}
+
+type FreezeResource struct {
+	Service  string `json:"service"`
+	Resource string `json:"resource"`
+}
+
+type Freeze struct {
+	Slug string `json:"slug"`
+	FreezeRequest
+}
+
+func (config *Client) CreateFreeze(rawreq FreezeRequest, idempotencyKey string) (*Freeze, error) {
+	body, err := json.Marshal(rawreq)
+	if err != nil {
+		return nil, fmt.Errorf("Internal error creating request")
+	}
+
+	statusCode, output, err := config.doIdempotent(http.MethodPost, "freezes", idempotencyKey, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusBadRequest {
+		var res Error
+		err = json.Unmarshal(output, &res)
+		if err != nil {
+			return nil, fmt.Errorf("Unhandled bad request decode: %w", err)
+		}
+
+		return nil, fmt.Errorf("Unhandled http-error-code: %v", res)
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unhandled http status code [%v]", statusCode)
+	}
+
+	var res Freeze
+	err = json.Unmarshal(output, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode resposne from server: %w", err)
+	}
+
+	return &res, nil
+}
+
+func (config *Client) ReadFreeze(slug string) (*Freeze, error) {
+	path := fmt.Sprintf("freeze/%s", url.PathEscape(slug))
+	statusCode, output, err := config.do(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unhandled http status code [%v]", statusCode)
+	}
+
+	var res Freeze
+	err = json.Unmarshal(output, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode resposne from server: %w", err)
+	}
+
+	return &res, nil
+}
+
+func (config *Client) UpdateFreeze(slug string, rawreq FreezeRequest) (*Freeze, error) {
+	body, err := json.Marshal(rawreq)
+	if err != nil {
+		return nil, fmt.Errorf("Internal error creating request")
+	}
+
+	path := fmt.Sprintf("freeze/%s", url.PathEscape(slug))
+	statusCode, output, err := config.do(http.MethodPost, path, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusBadRequest {
+		var res Error
+		err = json.Unmarshal(output, &res)
+		if err != nil {
+			return nil, fmt.Errorf("Unhandled bad request decode: %w", err)
+		}
+
+		return nil, fmt.Errorf("Unhandled http-error-code: %v", res)
+	}
+
+	if statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("Freeze not found.")
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unhandled http status code [%v]", statusCode)
+	}
+
+	var res Freeze
+	err = json.Unmarshal(output, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode resposne from server: %w", err)
+	}
+
+	return &res, nil
+}
+
+func (config *Client) DeleteFreeze(slug string) error {
+	path := fmt.Sprintf("freeze/%s", url.PathEscape(slug))
+	statusCode, _, err := config.do(http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+
+	if statusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("unhandled http status code [%v]", statusCode)
+	}
+
+	return nil
+}
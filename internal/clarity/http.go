@@ -1,6 +1,8 @@
 package clarity
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -8,11 +10,27 @@ import (
 )
 
 const (
-	Authorization = "Authorization"
+	Authorization  = "Authorization"
+	IdempotencyKey = "Idempotency-Key"
 )
 
+// NewIdempotencyKey returns a random key identifying a single logical create,
+// every attempt of that create must send the same key so the server is able
+// to deduplicate them.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("reading random bytes: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
 //lowtech
 func (config *Client) do(method string, path string, payload io.Reader) (int, []byte, error) {
+	return config.doIdempotent(method, path, "", payload)
+}
+
+func (config *Client) doIdempotent(method string, path string, idempotencyKey string, payload io.Reader) (int, []byte, error) {
 	endpoint := fmt.Sprintf("%s/%s", config.Host, path)
 	req, err := http.NewRequest(method, endpoint, payload)
 	if err != nil {
@@ -20,9 +38,15 @@ func (config *Client) do(method string, path string, payload io.Reader) (int, []
 	}
 
 	req.Header.Set(Authorization, fmt.Sprintf("Bearer %s", config.Token))
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyKey, idempotencyKey)
+	}
 
 	rsp, err := config.Client.Do(req)
 	if err != nil {
+		if idempotencyKey != "" {
+			return 0, nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
 		return 0, nil, err
 	}
 	defer rsp.Body.Close()
@@ -32,6 +56,10 @@ func (config *Client) do(method string, path string, payload io.Reader) (int, []
 		return 0, nil, err
 	}
 
+	if idempotencyKey != "" && rsp.StatusCode >= http.StatusInternalServerError {
+		return rsp.StatusCode, output, fmt.Errorf("%w: http status code [%v]", ErrUnavailable, rsp.StatusCode)
+	}
+
 	return rsp.StatusCode, output, nil
 
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type Provider struct {
@@ -14,17 +15,75 @@ type Provider struct {
 	Capabilities []string     `json:"capabilities"`
 }
 
+// CanDeploy reports whether the provider lists the resource type among its
+// capabilities.
+func (p Provider) CanDeploy(resourceType string) bool {
+	for _, c := range p.Capabilities {
+		if c == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
 type TypeSwitch struct {
 	Type string `json:"type"`
 }
 
 var AWSProviderType = TypeSwitch{"aws"}
 var WebhookProviderType = TypeSwitch{"webhook"}
+var GCPProviderType = TypeSwitch{"gcp"}
+
+// providerResourceTypes lists the resource types each provider type may
+// deploy to. Webhook providers hand deployments off and accept any type.
+var providerResourceTypes = map[string][]string{
+	AWSProviderType.Type: {ResourceTypeLambda, ResourceTypeECS, ResourceTypeKubernetes},
+	GCPProviderType.Type: {ResourceTypeCloudRun, ResourceTypeKubernetes},
+}
+
+// SupportsResourceType reports whether the provider's type is compatible with
+// the resource type. Provider types unknown to this client are assumed to be
+// compatible.
+func (p Provider) SupportsResourceType(resourceType string) bool {
+	types, ok := providerResourceTypes[p.Info.Type]
+	if !ok {
+		return true
+	}
+
+	for _, t := range types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
 
 type ProviderInfo struct {
 	TypeSwitch
 	*AWS
 	*Webhook
+	*GCP
+	*Unknown
+}
+
+// Unknown marks a provider type this client does not recognise, the original
+// payload is preserved so newer servers keep working with older clients.
+type Unknown struct {
+	Raw json.RawMessage `json:"-"`
+}
+
+// IsUnknown reports whether the provider type was not recognised when decoding.
+func (t ProviderInfo) IsUnknown() bool {
+	return t.Unknown != nil
+}
+
+func (t ProviderInfo) MarshalJSON() ([]byte, error) {
+	if t.Unknown != nil {
+		return t.Unknown.Raw, nil
+	}
+
+	type plain ProviderInfo
+	return json.Marshal(plain(t))
 }
 
 func (t *ProviderInfo) UnmarshalJSON(data []byte) error {
@@ -38,24 +97,36 @@ func (t *ProviderInfo) UnmarshalJSON(data []byte) error {
 	case "webhook":
 		t.Webhook = &Webhook{}
 		return json.Unmarshal(data, t.Webhook)
+	case "gcp":
+		t.GCP = &GCP{}
+		return json.Unmarshal(data, t.GCP)
 	default:
-		return fmt.Errorf("unrecognized type value %q", t.Type)
+		t.Unknown = &Unknown{
+			Raw: append(json.RawMessage(nil), data...),
+		}
+		return nil
 	}
 
 }
 
 type AWS struct {
-	AccountID           string  `json:"account_id"`
-	AdditionalAccountID *string `json:"additional_account_id,omitempty"`
-	Role                string  `json:"role"`
-	Region              string  `json:"region"`
+	AccountID            string   `json:"account_id"`
+	AdditionalAccountID  *string  `json:"additional_account_id,omitempty"`
+	AdditionalAccountIDs []string `json:"additional_account_ids,omitempty"`
+	ExternalID           *string  `json:"external_id,omitempty"`
+	Role                 string   `json:"role"`
+	Region               string   `json:"region"`
 }
 
 type Webhook struct {
 	URL string `json:"url"`
 }
 
-func (config *Client) CreateProvider(name string, info ProviderInfo) (*Provider, error) {
+type GCP struct {
+	Project string `json:"project"`
+}
+
+func (config *Client) CreateProvider(name string, info ProviderInfo, idempotencyKey string) (*Provider, error) {
 	body, err := json.Marshal(struct {
 		Name     string       `json:"name"`
 		Provider ProviderInfo `json:"info"`
@@ -66,7 +137,7 @@ func (config *Client) CreateProvider(name string, info ProviderInfo) (*Provider,
 	if err != nil {
 		return nil, fmt.Errorf("Internal error creating request")
 	}
-	statusCode, output, err := config.do(http.MethodPost, "providers", bytes.NewBuffer(body))
+	statusCode, output, err := config.doIdempotent(http.MethodPost, "providers", idempotencyKey, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +155,7 @@ func (config *Client) CreateProvider(name string, info ProviderInfo) (*Provider,
 }
 
 func (config *Client) LoadProvider(slug string) (*Provider, error) {
-	path := fmt.Sprintf("provider/%s", slug)
+	path := fmt.Sprintf("provider/%s", url.PathEscape(slug))
 	statusCode, output, err := config.do(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -108,7 +179,7 @@ func (config *Client) LoadProvider(slug string) (*Provider, error) {
 }
 
 func (config *Client) DeleteProvider(slug string) error {
-	path := fmt.Sprintf("provider/%s", slug)
+	path := fmt.Sprintf("provider/%s", url.PathEscape(slug))
 	statusCode, output, err := config.do(http.MethodDelete, path, nil)
 	if statusCode == http.StatusBadRequest {
 		var res Error
@@ -149,7 +220,7 @@ func (config *Client) updateProviderName(slug string, name string) (*Provider, e
 		return nil, fmt.Errorf("Internal error creating request")
 	}
 
-	path := fmt.Sprintf("provider/%s", slug)
+	path := fmt.Sprintf("provider/%s", url.PathEscape(slug))
 	statusCode, output, err := config.do(http.MethodPost, path, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
@@ -2,6 +2,8 @@ package clarity
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -34,3 +36,122 @@ func TestSerialization(t *testing.T) {
 	}
 	require.Equal(t, expected, p.Info)
 }
+
+var unknownSnapshot = `
+{
+  "providers": [
+    {
+      "name": "future",
+      "slug": "future",
+      "info": {
+        "type": "future",
+        "endpoint": "https://future.example.com"
+      }
+    }
+  ]
+}
+`
+
+func TestUnknownProviderType(t *testing.T) {
+	var res struct {
+		Providers []Provider `json:"providers"`
+	}
+	err := json.Unmarshal([]byte(unknownSnapshot), &res)
+	require.NoError(t, err)
+	require.Len(t, res.Providers, 1)
+
+	info := res.Providers[0].Info
+	require.True(t, info.IsUnknown())
+	require.Equal(t, "future", info.Type)
+	require.Nil(t, info.AWS)
+	require.Nil(t, info.Webhook)
+	require.Nil(t, info.GCP)
+
+	out, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "future", "endpoint": "https://future.example.com"}`, string(out))
+}
+
+func TestGCPProviderType(t *testing.T) {
+	var info ProviderInfo
+	err := json.Unmarshal([]byte(`{"type": "gcp", "project": "my-project"}`), &info)
+	require.NoError(t, err)
+	require.False(t, info.IsUnknown())
+	require.Equal(t, GCPProviderType, info.TypeSwitch)
+	require.Equal(t, &GCP{Project: "my-project"}, info.GCP)
+
+	out, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "gcp", "project": "my-project"}`, string(out))
+}
+
+func TestKnownProviderTypeRoundTrip(t *testing.T) {
+	var p Provider
+	err := json.Unmarshal([]byte(createSnapshot), &p)
+	require.NoError(t, err)
+	require.False(t, p.Info.IsUnknown())
+
+	out, err := json.Marshal(p.Info)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "aws", "account_id": "01234576", "role": "role-name", "region": "us-east-1"}`, string(out))
+}
+
+func TestCanDeploy(t *testing.T) {
+	p := Provider{Capabilities: []string{ResourceTypeLambda}}
+	require.True(t, p.CanDeploy(ResourceTypeLambda))
+	require.False(t, p.CanDeploy("unknown"))
+	require.False(t, Provider{}.CanDeploy(ResourceTypeLambda))
+}
+
+func TestProviderSupportsResourceType(t *testing.T) {
+	aws := Provider{Info: ProviderInfo{TypeSwitch: AWSProviderType}}
+	require.True(t, aws.SupportsResourceType(ResourceTypeLambda))
+	require.True(t, aws.SupportsResourceType(ResourceTypeKubernetes))
+	require.False(t, aws.SupportsResourceType(ResourceTypeCloudRun))
+
+	gcp := Provider{Info: ProviderInfo{TypeSwitch: GCPProviderType}}
+	require.True(t, gcp.SupportsResourceType(ResourceTypeCloudRun))
+	require.False(t, gcp.SupportsResourceType(ResourceTypeECS))
+
+	webhook := Provider{Info: ProviderInfo{TypeSwitch: WebhookProviderType}}
+	require.True(t, webhook.SupportsResourceType(ResourceTypeCloudRun))
+}
+
+func TestCreateProviderIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKey))
+		w.Write([]byte(createSnapshot))
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL, Token: "token", Client: server.Client()}
+	info := ProviderInfo{TypeSwitch: WebhookProviderType, Webhook: &Webhook{URL: "https://example.com"}}
+
+	key := NewIdempotencyKey()
+	_, err := client.CreateProvider("hey", info, key)
+	require.NoError(t, err)
+	_, err = client.CreateProvider("hey", info, key)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{key, key}, keys)
+	require.NotEqual(t, key, NewIdempotencyKey())
+}
+
+func TestCreateProviderUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &Client{Host: server.URL, Token: "token", Client: server.Client()}
+	info := ProviderInfo{TypeSwitch: WebhookProviderType, Webhook: &Webhook{URL: "https://example.com"}}
+
+	_, err := client.CreateProvider("hey", info, NewIdempotencyKey())
+	require.ErrorIs(t, err, ErrUnavailable)
+
+	// Without a key the failure is not marked as safe to repeat.
+	_, err = client.LoadProviders()
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnavailable)
+}
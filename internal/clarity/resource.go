@@ -5,6 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+)
+
+const (
+	ResourceTypeLambda     = "lambda"
+	ResourceTypeECS        = "ecs"
+	ResourceTypeKubernetes = "kubernetes"
+	ResourceTypeCloudRun   = "cloud-run"
 )
 
 type Resource struct {
@@ -18,79 +26,172 @@ type InternalResource struct {
 	// For read
 	Data       Configuration      `json:"data"`
 	Deployment DeploymentStrategy `json:"deployment"`
+	// Slug of the resource in the same service a version must have succeeded
+	// in before it is eligible for this resource.
+	PromoteFrom string `json:"promote_from"`
 }
 
 func (x InternalResource) ManualUserInterfaceTrigger() bool {
-	trigger := x.Deployment.Trigger[0]
-	return trigger.Type == "event" && trigger.Event == "manual"
+	return x.Deployment.ManualUserInterfaceTrigger()
 }
 
-func (x InternalResource) updateStrategyRequest(single DeploymentRule) UpdateDeploymentStrategy {
-	strategy := x.Deployment
-	strategy.Trigger = []DeploymentRule{
-		single,
-	}
-
-	return UpdateDeploymentStrategy{
-		Strategy: strategy,
-	}
+// UpdateResourceRequest renames a resource or changes its configuration, such
+// as the Lambda alias, keeping its deployment history.
+type UpdateResourceRequest struct {
+	Name          string        `json:"name"`
+	Configuration Configuration `json:"configuration"`
+	PromoteFrom   string        `json:"promote_from"`
 }
 
-func (x InternalResource) EnableUserInterfaceTrigger() UpdateDeploymentStrategy {
-	return x.updateStrategyRequest(DeploymentRule{
-		Type:  "event",
-		Event: "manual",
-	})
-}
+const (
+	// RequestTypeImport registers an existing resource, such as a Lambda alias.
+	RequestTypeImport = "import"
+	// RequestTypeCreate has Clarity create the resource, such as a Lambda alias
+	// pointing at a published version.
+	RequestTypeCreate = "create"
+)
 
-func (x InternalResource) DisableUserInterfaceTrigger() UpdateDeploymentStrategy {
-	return x.updateStrategyRequest(DeploymentRule{
-		Type:  "always",
-		Event: "",
-	})
+type CreateResourceRequest struct {
+	Name          string        `json:"name"`
+	Provider      string        `json:"provider"` // slug
+	RequestType   string        `json:"request_type"`
+	Configuration Configuration `json:"configuration"`
+	PromoteFrom   string        `json:"promote_from,omitempty"`
 }
 
-type UpdateDeploymentStrategy struct {
-	Strategy DeploymentStrategy `json:"strategy"`
+var LambdaResourceType = TypeSwitch{ResourceTypeLambda}
+
+// lambdaRequestType is the type Lambda configurations are sent with, the
+// server reports them back as 'lambda'.
+const lambdaRequestType = "aws"
+
+var ECSResourceType = TypeSwitch{ResourceTypeECS}
+var KubernetesResourceType = TypeSwitch{ResourceTypeKubernetes}
+var CloudRunResourceType = TypeSwitch{ResourceTypeCloudRun}
+
+// Configuration is the type specific configuration of a resource, encoded as
+// the resource type alongside a nested 'configuration' object.
+type Configuration struct {
+	TypeSwitch
+	*LambdaConfiguration
+	*ECSConfiguration
+	*KubernetesConfiguration
+	*CloudRunConfiguration
+	*Unknown
 }
 
-type DeploymentStrategy struct {
-	Trigger    []DeploymentRule `json:"trigger"`
-	Health     []interface{}    `json:"health"`
-	Evaluation []interface{}    `json:"evaluation"`
-	Stages     []interface{}    `json:"stages"`
+type configurationJSON struct {
+	Type          string          `json:"type"`
+	Configuration json.RawMessage `json:"configuration"`
 }
 
-type DeploymentRule struct {
-	Type  string `json:"type"`
-	Event string `json:"name,omitempty"`
+// IsUnknown reports whether the resource type was not recognised when decoding.
+func (x Configuration) IsUnknown() bool {
+	return x.Unknown != nil
 }
 
-type CreateResourceRequest struct {
-	Name          string        `json:"name"`
-	Provider      string        `json:"provider"`     // slug
-	RequestType   string        `json:"request_type"` // "import"
-	Configuration Configuration `json:"configuration"`
+func (x Configuration) MarshalJSON() ([]byte, error) {
+	conf, typ := interface{}(nil), x.Type
+	switch {
+	case x.Unknown != nil:
+		return x.Unknown.Raw, nil
+	case x.LambdaConfiguration != nil:
+		conf, typ = x.LambdaConfiguration, lambdaRequestType
+	case x.ECSConfiguration != nil:
+		conf = x.ECSConfiguration
+	case x.KubernetesConfiguration != nil:
+		conf = x.KubernetesConfiguration
+	case x.CloudRunConfiguration != nil:
+		conf = x.CloudRunConfiguration
+	}
+
+	return json.Marshal(struct {
+		Type          string      `json:"type"`
+		Configuration interface{} `json:"configuration"`
+	}{
+		Type:          typ,
+		Configuration: conf,
+	})
 }
 
-type Configuration struct {
-	Type string `json:"type"` // "lambda"
-	// FIX bug here
-	LambdaConfiguration LambdaConfiguration `json:"configuration"`
+func (x *Configuration) UnmarshalJSON(data []byte) error {
+	var raw configurationJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	x.Type = raw.Type
+	switch raw.Type {
+	case ResourceTypeLambda, lambdaRequestType:
+		x.Type = ResourceTypeLambda
+		x.LambdaConfiguration = &LambdaConfiguration{}
+		return json.Unmarshal(raw.Configuration, x.LambdaConfiguration)
+	case ResourceTypeECS:
+		x.ECSConfiguration = &ECSConfiguration{}
+		return json.Unmarshal(raw.Configuration, x.ECSConfiguration)
+	case ResourceTypeKubernetes:
+		x.KubernetesConfiguration = &KubernetesConfiguration{}
+		return json.Unmarshal(raw.Configuration, x.KubernetesConfiguration)
+	case ResourceTypeCloudRun:
+		x.CloudRunConfiguration = &CloudRunConfiguration{}
+		return json.Unmarshal(raw.Configuration, x.CloudRunConfiguration)
+	default:
+		x.Unknown = &Unknown{
+			Raw: append(json.RawMessage(nil), data...),
+		}
+		return nil
+	}
 }
 
 func (x Configuration) resourceName() string {
-	// FIX aws
-	return fmt.Sprintf("%s:%s", x.LambdaConfiguration.Name, x.LambdaConfiguration.Alias)
+	switch {
+	case x.LambdaConfiguration != nil:
+		return fmt.Sprintf("%s:%s", x.LambdaConfiguration.Name, x.LambdaConfiguration.Alias)
+	case x.ECSConfiguration != nil:
+		return fmt.Sprintf("%s/%s", x.ECSConfiguration.Cluster, x.ECSConfiguration.Service)
+	case x.KubernetesConfiguration != nil:
+		return fmt.Sprintf("%s/%s", x.KubernetesConfiguration.Namespace, x.KubernetesConfiguration.Deployment)
+	case x.CloudRunConfiguration != nil:
+		return fmt.Sprintf("%s/%s/%s", x.CloudRunConfiguration.Project, x.CloudRunConfiguration.Region, x.CloudRunConfiguration.Service)
+	default:
+		return x.Type
+	}
 }
 
 type LambdaConfiguration struct {
 	Name  string `json:"name"`
 	Alias string `json:"alias,omitempty"`
+	// Version the alias is created pointing at, only used when creating. When
+	// empty a version is published from $LATEST.
+	Version string `json:"version,omitempty"`
+}
+
+// ECSConfiguration identifies the container of an ECS service, including
+// services running on Fargate.
+type ECSConfiguration struct {
+	Cluster   string `json:"cluster"`
+	Service   string `json:"service"`
+	Container string `json:"container"`
+}
+
+// KubernetesConfiguration identifies the container of a Kubernetes Deployment,
+// the cluster is reached through the resource's provider.
+type KubernetesConfiguration struct {
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment"`
+	Container  string `json:"container"`
+}
+
+// CloudRunConfiguration identifies a Google Cloud Run service, new revisions
+// are addressed through the traffic tag while traffic is shifted.
+type CloudRunConfiguration struct {
+	Project    string `json:"project"`
+	Region     string `json:"region"`
+	Service    string `json:"service"`
+	TrafficTag string `json:"traffic_tag,omitempty"`
 }
 
 func (config *Client) ReadResource(serviceSlug string, resourceSlug string) (*InternalResource, error) {
-	path := fmt.Sprintf("service/%s/resource/%s", serviceSlug, resourceSlug)
+	path := fmt.Sprintf("service/%s/resource/%s", url.PathEscape(serviceSlug), url.PathEscape(resourceSlug))
 	statusCode, output, err := config.do(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -113,14 +214,14 @@ func (config *Client) ReadResource(serviceSlug string, resourceSlug string) (*In
 	return &res, nil
 }
 
-func (config *Client) CreateResource(serviceSlug string, rawreq CreateResourceRequest) (*InternalResource, error) {
+func (config *Client) CreateResource(serviceSlug string, rawreq CreateResourceRequest, idempotencyKey string) (*InternalResource, error) {
 	body, err := json.Marshal(rawreq)
 	if err != nil {
 		return nil, fmt.Errorf("Internal error creating request")
 	}
 
-	path := fmt.Sprintf("service/%s/resource", serviceSlug)
-	statusCode, output, err := config.do(http.MethodPost, path, bytes.NewBuffer(body))
+	path := fmt.Sprintf("service/%s/resource", url.PathEscape(serviceSlug))
+	statusCode, output, err := config.doIdempotent(http.MethodPost, path, idempotencyKey, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -156,8 +257,51 @@ func (config *Client) CreateResource(serviceSlug string, rawreq CreateResourceRe
 	return &res, nil
 }
 
+func (config *Client) UpdateResource(serviceSlug string, resourceSlug string, rawreq UpdateResourceRequest) (*InternalResource, error) {
+	body, err := json.Marshal(rawreq)
+	if err != nil {
+		return nil, fmt.Errorf("Internal error creating request")
+	}
+
+	path := fmt.Sprintf("service/%s/resource/%s", url.PathEscape(serviceSlug), url.PathEscape(resourceSlug))
+	statusCode, output, err := config.do(http.MethodPost, path, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusBadRequest {
+		var res Error
+		err = json.Unmarshal(output, &res)
+		if err != nil {
+			return nil, fmt.Errorf("Unhandled bad request decode: %w", err)
+		}
+
+		if res.Code == "service-candidate-not-found" {
+			return nil, fmt.Errorf("Could not find the underlying resource '%s'", rawreq.Configuration.resourceName())
+		}
+
+		return nil, fmt.Errorf("Unhandled http-error-code: %v", res)
+	}
+
+	if statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("Service/Resource not found.")
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unhandled http status code [%v]", statusCode)
+	}
+
+	var res InternalResource
+	err = json.Unmarshal(output, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode resposne from server: %w", err)
+	}
+
+	return &res, nil
+}
+
 func (config *Client) DeleteResource(serviceSlug string, resourceSlug string) error {
-	path := fmt.Sprintf("service/%s/resource/%s", serviceSlug, resourceSlug)
+	path := fmt.Sprintf("service/%s/resource/%s", url.PathEscape(serviceSlug), url.PathEscape(resourceSlug))
 	statusCode, output, err := config.do(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
@@ -171,7 +315,7 @@ func (config *Client) DeleteResource(serviceSlug string, resourceSlug string) er
 		}
 
 		if res.Code == "deployment-in-progress" {
-			return fmt.Errorf("Unable to delete resource while there is an active deployment in progress")
+			return ErrDeploymentInProgress
 		}
 
 		return fmt.Errorf("Unhandled http-error-code: %v", res)
@@ -193,7 +337,7 @@ func (config *Client) UpdateResourceDeploymentStrategy(serviceSlug string, resou
 		return fmt.Errorf("Internal error creating request")
 	}
 
-	path := fmt.Sprintf("service/%s/resource/%s/strategy", serviceSlug, resourceSlug)
+	path := fmt.Sprintf("service/%s/resource/%s/strategy", url.PathEscape(serviceSlug), url.PathEscape(resourceSlug))
 	statusCode, _, err := config.do(http.MethodPost, path, bytes.NewBuffer(body))
 	if err != nil {
 		return err
@@ -0,0 +1,67 @@
+package clarity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurationSerialization(t *testing.T) {
+	lambda := Configuration{
+		TypeSwitch:          LambdaResourceType,
+		LambdaConfiguration: &LambdaConfiguration{Name: "function", Alias: "clarity"},
+	}
+	ecs := Configuration{
+		TypeSwitch:       ECSResourceType,
+		ECSConfiguration: &ECSConfiguration{Cluster: "cluster", Service: "service", Container: "app"},
+	}
+	kubernetes := Configuration{
+		TypeSwitch:              KubernetesResourceType,
+		KubernetesConfiguration: &KubernetesConfiguration{Namespace: "default", Deployment: "web", Container: "app"},
+	}
+	cloudRun := Configuration{
+		TypeSwitch:            CloudRunResourceType,
+		CloudRunConfiguration: &CloudRunConfiguration{Project: "project", Region: "europe-west1", Service: "web", TrafficTag: "clarity"},
+	}
+
+	cases := map[string]Configuration{
+		`{"type": "aws", "configuration": {"name": "function", "alias": "clarity"}}`:                                                           lambda,
+		`{"type": "ecs", "configuration": {"cluster": "cluster", "service": "service", "container": "app"}}`:                                   ecs,
+		`{"type": "kubernetes", "configuration": {"namespace": "default", "deployment": "web", "container": "app"}}`:                           kubernetes,
+		`{"type": "cloud-run", "configuration": {"project": "project", "region": "europe-west1", "service": "web", "traffic_tag": "clarity"}}`: cloudRun,
+	}
+	for snapshot, expected := range cases {
+		var c Configuration
+		require.NoError(t, json.Unmarshal([]byte(snapshot), &c))
+		require.Equal(t, expected, c)
+
+		out, err := json.Marshal(c)
+		require.NoError(t, err)
+		require.JSONEq(t, snapshot, string(out))
+	}
+}
+
+func TestConfigurationLambdaReadType(t *testing.T) {
+	var c Configuration
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "lambda", "configuration": {"name": "function"}}`), &c))
+	require.Equal(t, LambdaResourceType, c.TypeSwitch)
+	require.Equal(t, &LambdaConfiguration{Name: "function"}, c.LambdaConfiguration)
+
+	out, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "aws", "configuration": {"name": "function"}}`, string(out))
+}
+
+func TestConfigurationUnknownType(t *testing.T) {
+	snapshot := `{"type": "future", "configuration": {"name": "x"}}`
+
+	var c Configuration
+	require.NoError(t, json.Unmarshal([]byte(snapshot), &c))
+	require.True(t, c.IsUnknown())
+	require.Equal(t, "future", c.Type)
+
+	out, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.JSONEq(t, snapshot, string(out))
+}
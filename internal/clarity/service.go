@@ -5,13 +5,71 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
+const (
+	ServiceTypeFunction   = "function"
+	ServiceTypeContainer  = "container"
+	ServiceTypeStaticSite = "static-site"
+)
+
+var ServiceTypes = []string{
+	ServiceTypeFunction,
+	ServiceTypeContainer,
+	ServiceTypeStaticSite,
+}
+
+// serviceResourceTypes lists the resource types each service type may deploy to.
+var serviceResourceTypes = map[string][]string{
+	ServiceTypeFunction:   {ResourceTypeLambda},
+	ServiceTypeContainer:  {ResourceTypeECS, ResourceTypeKubernetes, ResourceTypeCloudRun},
+	ServiceTypeStaticSite: {},
+}
+
+// SupportsResourceType reports whether resources of the given type may be
+// attached to the service. Service types unknown to this client are assumed
+// to be compatible.
+func (s Service) SupportsResourceType(resourceType string) bool {
+	types, ok := serviceResourceTypes[s.ServiceType]
+	if !ok {
+		return true
+	}
+
+	for _, t := range types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
 type ServiceCreateRequest struct {
 	Name               string                  `json:"name"`
 	Resources          []CreateResourceRequest `json:"resources"`
 	RepositoryProvider string                  `json:"repository_provider"`
 	ServiceType        string                  `json:"type"`
+	ServiceMetadata
+}
+
+type ServiceUpdateRequest struct {
+	Name               string `json:"name"`
+	RepositoryProvider string `json:"repository_provider"`
+	ServiceType        string `json:"type"`
+	ServiceMetadata
+}
+
+// ServiceMetadata describes the ownership of a service for the service catalogue.
+type ServiceMetadata struct {
+	Description   string        `json:"description"`
+	OwnerTeam     string        `json:"owner_team"`
+	RepositoryURL string        `json:"repository_url"`
+	Links         []ServiceLink `json:"links"`
+}
+
+type ServiceLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
 }
 
 type ServicesListResponse struct {
@@ -24,15 +82,16 @@ type Service struct {
 	Resources   []Resource `json:"resources"`
 	Provider    *Provider  `json:"repository_provider"`
 	ServiceType string     `json:"type"`
+	ServiceMetadata
 }
 
-func (config *Client) CreateService(rawreq ServiceCreateRequest) (*Service, error) {
+func (config *Client) CreateService(rawreq ServiceCreateRequest, idempotencyKey string) (*Service, error) {
 	body, err := json.Marshal(rawreq)
 	if err != nil {
 		return nil, fmt.Errorf("Internal error creating request")
 	}
 
-	statusCode, output, err := config.do(http.MethodPost, "services", bytes.NewBuffer(body))
+	statusCode, output, err := config.doIdempotent(http.MethodPost, "services", idempotencyKey, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +109,47 @@ func (config *Client) CreateService(rawreq ServiceCreateRequest) (*Service, erro
 	return &res, nil
 }
 
+func (config *Client) UpdateService(serviceSlug string, rawreq ServiceUpdateRequest) (*Service, error) {
+	body, err := json.Marshal(rawreq)
+	if err != nil {
+		return nil, fmt.Errorf("Internal error creating request")
+	}
+
+	path := fmt.Sprintf("service/%s", url.PathEscape(serviceSlug))
+	statusCode, output, err := config.do(http.MethodPost, path, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusBadRequest {
+		var res Error
+		err = json.Unmarshal(output, &res)
+		if err != nil {
+			return nil, fmt.Errorf("Unhandled bad request decode: %w", err)
+		}
+
+		return nil, fmt.Errorf("Unhandled http-error-code: %v", res)
+	}
+
+	if statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("Service slug not found.")
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unhandled http status code [%v]", statusCode)
+	}
+
+	var res Service
+	err = json.Unmarshal(output, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode resposne from server: %w", err)
+	}
+
+	return &res, nil
+}
+
 func (config *Client) DeleteService(serviceSlug string) error {
-	statusCode, output, err := config.do(http.MethodDelete, fmt.Sprintf("service/%s", serviceSlug), nil)
+	statusCode, output, err := config.do(http.MethodDelete, fmt.Sprintf("service/%s", url.PathEscape(serviceSlug)), nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,24 @@
+package clarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportsResourceType(t *testing.T) {
+	function := Service{ServiceType: ServiceTypeFunction}
+	require.True(t, function.SupportsResourceType(ResourceTypeLambda))
+	require.False(t, function.SupportsResourceType(ResourceTypeECS))
+
+	container := Service{ServiceType: ServiceTypeContainer}
+	require.True(t, container.SupportsResourceType(ResourceTypeECS))
+	require.True(t, container.SupportsResourceType(ResourceTypeKubernetes))
+	require.False(t, container.SupportsResourceType(ResourceTypeLambda))
+
+	site := Service{ServiceType: ServiceTypeStaticSite}
+	require.False(t, site.SupportsResourceType(ResourceTypeLambda))
+
+	future := Service{ServiceType: "future"}
+	require.True(t, future.SupportsResourceType(ResourceTypeLambda))
+}
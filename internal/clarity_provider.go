@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
 
 	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -13,7 +15,25 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-// var regionRegexp = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d$`)
+var accountIDRegexp = regexp.MustCompile(`^\d{12}$`)
+var externalIDRegexp = regexp.MustCompile(`^[\w+=,.@:/-]*$`)
+
+func validateAWSAccountID() schema.SchemaValidateFunc {
+	return validation.StringMatch(accountIDRegexp, "must be a 12 digit AWS account ID")
+}
+
+func validateAWSRegion(v interface{}, k string) (ws []string, errs []error) {
+	region, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if awsPartitionForRegion(region) == "" {
+		errs = append(errs, fmt.Errorf("%s: unknown AWS region '%s'", k, region))
+	}
+	return
+}
 
 func providerResource() *schema.Resource {
 	return &schema.Resource{
@@ -22,9 +42,15 @@ func providerResource() *schema.Resource {
 
 		CreateContext: providerCreate,
 		ReadContext:   providerRead,
+		UpdateContext: providerUpdate,
 		DeleteContext: providerDelete,
+		CustomizeDiff: providerCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: providerImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -50,14 +76,35 @@ func providerResource() *schema.Resource {
 							Type:         schema.TypeString,
 							ForceNew:     true,
 							Required:     true,
-							ValidateFunc: validation.StringLenBetween(12, 12),
+							ValidateFunc: validateAWSAccountID(),
 						},
 						"additional_account_id": {
-							Description:  "Additional AWS Account ID",
+							Description:  "Additional AWS Account ID. Conflicts with `additional_account_ids`.",
 							Type:         schema.TypeString,
 							ForceNew:     true,
 							Optional:     true,
-							ValidateFunc: validation.StringLenBetween(12, 12),
+							Deprecated:   "Use 'additional_account_ids' instead.",
+							ValidateFunc: validateAWSAccountID(),
+						},
+						"additional_account_ids": {
+							Description: "Additional AWS Account IDs",
+							Type:        schema.TypeList,
+							ForceNew:    true,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateAWSAccountID(),
+							},
+						},
+						"external_id": {
+							Description: "External ID required by the IAM Role trust policy",
+							Type:        schema.TypeString,
+							ForceNew:    true,
+							Optional:    true,
+							ValidateFunc: validation.All(
+								validation.StringLenBetween(2, 1224),
+								validation.StringMatch(externalIDRegexp, "must only contain alphanumeric characters and any of +=,.@:/-"),
+							),
 						},
 						"role": {
 							Description:  "IAM Role name",
@@ -67,11 +114,11 @@ func providerResource() *schema.Resource {
 							ValidateFunc: validation.StringLenBetween(0, 255),
 						},
 						"region": {
-							Description:  "AWS Region",
+							Description:  "AWS Region, including GovCloud and China regions",
 							Type:         schema.TypeString,
 							ForceNew:     true,
 							Required:     true,
-							ValidateFunc: validation.StringLenBetween(0, 20),
+							ValidateFunc: validateAWSRegion,
 						},
 					},
 				},
@@ -102,6 +149,20 @@ func providerResource() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
+			"force_destroy": {
+				Description: "Delete all services and resources attached to this provider when destroying it.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"adopt_existing":  adoptExistingSchema(),
+			"idempotency_key": idempotencyKeySchema(),
+			"capabilities": {
+				Description: "Resource types this provider is able to deploy.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
@@ -117,19 +178,32 @@ func providerCreate(ctx context.Context, d *schema.ResourceData, meta interface{
 		aws := v.(*schema.Set).List()[0].(map[string]interface{})
 		account_id := aws["account_id"].(string)
 		var additional_account_id *string
-		if v, ok := aws["additional_account_id"]; ok {
+		if v, ok := aws["additional_account_id"]; ok && len(v.(string)) > 0 {
 			s := v.(string)
 			additional_account_id = &s
 		}
+		var additional_account_ids []string
+		if v, ok := aws["additional_account_ids"]; ok {
+			for _, id := range v.([]interface{}) {
+				additional_account_ids = append(additional_account_ids, id.(string))
+			}
+		}
+		var external_id *string
+		if v, ok := aws["external_id"]; ok && len(v.(string)) > 0 {
+			s := v.(string)
+			external_id = &s
+		}
 		role := aws["role"].(string)
 		region := aws["region"].(string)
 
 		info.TypeSwitch = clarity.AWSProviderType
 		info.AWS = &clarity.AWS{
-			AccountID:           account_id,
-			AdditionalAccountID: additional_account_id,
-			Role:                role,
-			Region:              region,
+			AccountID:            account_id,
+			AdditionalAccountID:  additional_account_id,
+			AdditionalAccountIDs: additional_account_ids,
+			ExternalID:           external_id,
+			Role:                 role,
+			Region:               region,
 		}
 		typeSet++
 	}
@@ -155,12 +229,25 @@ func providerCreate(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	for _, p := range providers {
-		if p.Name == name {
-			return diag.Errorf("Conflict. A provider with the name '%s' already exists.", name)
+		if p.Name != name {
+			continue
 		}
+
+		if d.Get("adopt_existing").(bool) && sameProviderInfo(p.Info, info) {
+			d.SetId(p.Slug)
+			diags := diag.Diagnostics{adoptedWarning("provider", name, p.Slug)}
+			return append(diags, providerRead(ctx, d, meta)...)
+		}
+
+		return diag.Errorf("Conflict. A provider with the name '%s' already exists.", name)
 	}
 
-	provider, err := client.CreateProvider(name, info)
+	key := idempotencyKey(d)
+	var provider *clarity.Provider
+	err = createWithRetry(ctx, d.Timeout(schema.TimeoutCreate), func() (err error) {
+		provider, err = client.CreateProvider(name, info, key)
+		return err
+	})
 	if err != nil {
 		return diag.Errorf("creating provider: %v", err)
 	}
@@ -172,6 +259,27 @@ func providerCreate(ctx context.Context, d *schema.ResourceData, meta interface{
 	return providerRead(ctx, d, meta)
 }
 
+func providerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := planIdempotencyKey(d); err != nil {
+		return err
+	}
+
+	return validateAdditionalAccountIDs(d.Get("aws").(*schema.Set).List())
+}
+
+// validateAdditionalAccountIDs rejects setting both the deprecated
+// additional_account_id and additional_account_ids. ConflictsWith can not
+// reference attributes within the aws set.
+func validateAdditionalAccountIDs(in []interface{}) error {
+	for _, a := range in {
+		m := a.(map[string]interface{})
+		if m["additional_account_id"].(string) != "" && len(m["additional_account_ids"].([]interface{})) > 0 {
+			return fmt.Errorf("AWS 'additional_account_id' conflicts with 'additional_account_ids', move the account into 'additional_account_ids'")
+		}
+	}
+	return nil
+}
+
 func mapHash(in interface{}) int {
 	var buf bytes.Buffer
 	m := in.(map[string]interface{})
@@ -211,7 +319,17 @@ func providerRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 		}
 
 		if rsp.Info.AWS.AdditionalAccountID != nil && len(*rsp.Info.AWS.AdditionalAccountID) > 0 {
-			s["additional_account_id"] = rsp.Info.AWS.AdditionalAccountID
+			s["additional_account_id"] = *rsp.Info.AWS.AdditionalAccountID
+		}
+		if len(rsp.Info.AWS.AdditionalAccountIDs) > 0 {
+			ids := make([]interface{}, 0, len(rsp.Info.AWS.AdditionalAccountIDs))
+			for _, id := range rsp.Info.AWS.AdditionalAccountIDs {
+				ids = append(ids, id)
+			}
+			s["additional_account_ids"] = ids
+		}
+		if rsp.Info.AWS.ExternalID != nil && len(*rsp.Info.AWS.ExternalID) > 0 {
+			s["external_id"] = *rsp.Info.AWS.ExternalID
 		}
 		d.Set("aws", schema.NewSet(mapHash, []interface{}{s}))
 	}
@@ -224,13 +342,46 @@ func providerRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 	}
 
 	d.Set("slug", rsp.Slug)
+	d.Set("capabilities", rsp.Capabilities)
 
-	return nil
+	return unknownProviderDiagnostics(rsp)
+}
+
+// unknownProviderDiagnostics warns when a provider has a type this version of
+// the terraform provider does not recognise, rather than failing outright.
+func unknownProviderDiagnostics(p *clarity.Provider) diag.Diagnostics {
+	if !p.Info.IsUnknown() {
+		return nil
+	}
+
+	return diag.Diagnostics{unknownTypeWarning("provider", p.Info.Type, p.Slug)}
+}
+
+// unknownTypeWarning describes an object whose type this version of the
+// terraform provider does not recognise.
+func unknownTypeWarning(kind string, objectType string, slug string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Unrecognized %s type '%s'", kind, objectType),
+		Detail:   fmt.Sprintf("The %s '%s' has a type not supported by this version of the terraform provider, its configuration will not be managed. Upgrade the terraform provider to manage it.", kind, slug),
+	}
+}
+
+// Only force_destroy may change in place and it is local to the terraform state.
+func providerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return providerRead(ctx, d, meta)
 }
 
 func providerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*clarity.Client)
 	slug := d.Id()
+
+	if d.Get("force_destroy").(bool) {
+		if err := deleteProviderDependents(ctx, client, slug, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("deleting services and resources attached to provider '%s': %v", slug, err)
+		}
+	}
+
 	err := client.DeleteProvider(slug)
 	return diag.FromErr(err)
 }
@@ -2,7 +2,6 @@ package internal
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -22,6 +21,12 @@ func providerDatasource() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"capabilities": {
+				Description: "Resource types this provider is able to deploy.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
@@ -35,27 +40,14 @@ func providerDatasourceRead(ctx context.Context, d *schema.ResourceData, meta in
 		return diag.FromErr(err)
 	}
 
-	matches := 0
-	for _, r := range rsp {
-		if r.Name == name {
-			matches++
-		}
-	}
-	if matches == 0 {
-		return diag.FromErr(fmt.Errorf("No matching provider found with the name '%s'", name))
-	}
-
-	if matches > 1 {
-		return diag.FromErr(fmt.Errorf("Found multiple providers with the name '%s'", name))
+	r, err := findProviderByName(rsp, name)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	for _, r := range rsp {
-		if r.Name == name {
-			d.Set("slug", r.Slug)
-			d.SetId(r.Slug)
-			return nil
-		}
-	}
+	d.Set("slug", r.Slug)
+	d.Set("capabilities", r.Capabilities)
+	d.SetId(r.Slug)
 
-	return nil
+	return unknownProviderDiagnostics(r)
 }
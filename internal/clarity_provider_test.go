@@ -30,7 +30,14 @@ func TestAccProvider(t *testing.T) {
 				ResourceName:            "clarity_provider.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"force_destroy"},
+				ImportStateVerifyIgnore: []string{"force_destroy", "idempotency_key"},
+			},
+			{
+				ResourceName:            "clarity_provider.test",
+				ImportState:             true,
+				ImportStateId:           "name:terraform-test",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"idempotency_key"},
 			},
 		},
 	})
@@ -76,3 +83,38 @@ resource "clarity_provider" "test" {
 }
 `, account, region, role)
 }
+
+func TestValidateAdditionalAccountIDs(t *testing.T) {
+	aws := func(id string, ids ...interface{}) []interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				"additional_account_id":  id,
+				"additional_account_ids": ids,
+			},
+		}
+	}
+
+	for _, in := range [][]interface{}{aws(""), aws("012345678901"), aws("", "012345678901")} {
+		if err := validateAdditionalAccountIDs(in); err != nil {
+			t.Errorf("expected %v to be valid: %v", in, err)
+		}
+	}
+
+	if err := validateAdditionalAccountIDs(aws("012345678901", "109876543210")); err == nil {
+		t.Errorf("expected an error when both are set")
+	}
+}
+
+func TestValidateAWSRegion(t *testing.T) {
+	for _, region := range []string{"us-east-1", "eu-west-2", "us-gov-west-1", "cn-northwest-1"} {
+		if _, errs := validateAWSRegion(region, "region"); len(errs) > 0 {
+			t.Errorf("expected region '%s' to be valid: %v", region, errs)
+		}
+	}
+
+	for _, region := range []string{"", "us-east", "mars-north-1", "US-EAST-1"} {
+		if _, errs := validateAWSRegion(region, "region"); len(errs) == 0 {
+			t.Errorf("expected region '%s' to be invalid", region)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func validateDuration(v interface{}, k string) (ws []string, errs []error) {
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: invalid duration '%s', expected a value such as '10m' or '1h30m'", k, value))
+		return
+	}
+	if duration < 0 {
+		errs = append(errs, fmt.Errorf("%s: duration must not be negative", k))
+	}
+	return
+}
+
+// Durations are read back in their canonical form, e.g. '10m' as '10m0s'.
+func suppressEquivalentDuration(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	o, err := time.ParseDuration(oldValue)
+	if err != nil {
+		return false
+	}
+	n, err := time.ParseDuration(newValue)
+	if err != nil {
+		return false
+	}
+	return o == n
+}
+
+func durationSeconds(value string) int {
+	duration, _ := time.ParseDuration(value)
+	return int(duration / time.Second)
+}
+
+func formatSeconds(seconds int) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func deploymentSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Deployment configuration.",
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Optional:    true,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"trigger": triggerSchema(),
+				"health_check": {
+					Description: "Health gates evaluated during the deployment, a failing gate halts and rolls back the deployment.",
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"cloudwatch_alarm": {
+								Description:  "Name of a CloudWatch alarm, the gate fails while the alarm is in the `ALARM` state. Conflicts with `http_url`.",
+								Type:         schema.TypeString,
+								Optional:     true,
+								ValidateFunc: validation.StringLenBetween(1, 255),
+							},
+							"http_url": {
+								Description:  "URL probed during the deployment, the gate fails on a non 2xx response. Conflicts with `cloudwatch_alarm`.",
+								Type:         schema.TypeString,
+								Optional:     true,
+								ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+							},
+							"evaluation_period": {
+								Description:      "How long the gate is evaluated for, e.g. `5m`.",
+								Type:             schema.TypeString,
+								Optional:         true,
+								Default:          "5m",
+								ValidateFunc:     validateDuration,
+								DiffSuppressFunc: suppressEquivalentDuration,
+							},
+						},
+					},
+				},
+				"evaluation": {
+					Description: "Metric thresholds evaluated against the new version, a breached threshold halts and rolls back the deployment.",
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"metric": {
+								Description:  "The metric evaluated, one of `error_rate` (percent), `latency_p99` (milliseconds) or `throttles` (count).",
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice(clarity.EvaluationMetrics, false),
+							},
+							"operator": {
+								Description:  "Comparison which fails the evaluation, one of `gt`, `gte`, `lt` or `lte`.",
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      clarity.OperatorGreaterThan,
+								ValidateFunc: validation.StringInSlice(clarity.EvaluationOperators, false),
+							},
+							"threshold": {
+								Description:  "Threshold the metric is compared to. When comparing to the baseline this is the percentage change from the pre-deployment value.",
+								Type:         schema.TypeFloat,
+								Required:     true,
+								ValidateFunc: validation.FloatAtLeast(0),
+							},
+							"compare_to_baseline": {
+								Description: "Compare the metric to its value prior to the deployment rather than an absolute threshold.",
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     false,
+							},
+						},
+					},
+				},
+				"stage": {
+					Description: "Traffic shifting stages, e.g. 10% then 50% then 100%. Without stages all traffic is shifted at once.",
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"traffic_percentage": {
+								Description:  "Percentage of traffic shifted to the new version during this stage.",
+								Type:         schema.TypeInt,
+								Required:     true,
+								ValidateFunc: validation.IntBetween(1, 100),
+							},
+							"bake_duration": {
+								Description:      "How long the stage runs before advancing, e.g. `10m`.",
+								Type:             schema.TypeString,
+								Optional:         true,
+								Default:          "0s",
+								ValidateFunc:     validateDuration,
+								DiffSuppressFunc: suppressEquivalentDuration,
+							},
+							"advance": {
+								Description:  "How the deployment advances to the next stage, `automatic` or `manual`.",
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      clarity.StageAdvanceAutomatic,
+								ValidateFunc: validation.StringInSlice([]string{clarity.StageAdvanceAutomatic, clarity.StageAdvanceManual}, false),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// expandDeployment applies the deployment block onto the current strategy.
+// The block's lists replace the server's, an empty list clears it. Parts of
+// the strategy not managed by the block are left untouched.
+func expandDeployment(in []interface{}, current clarity.DeploymentStrategy) clarity.DeploymentStrategy {
+	strategy := current
+	if len(in) == 0 || in[0] == nil {
+		return strategy
+	}
+	deployment := in[0].(map[string]interface{})
+
+	strategy.Trigger = expandTriggers(deployment["trigger"].([]interface{}))
+	strategy.Stages = expandStages(deployment["stage"].([]interface{}))
+	strategy.Health = expandHealthChecks(deployment["health_check"].([]interface{}))
+	strategy.Evaluation = expandEvaluation(deployment["evaluation"].([]interface{}))
+
+	return strategy
+}
+
+func expandStages(in []interface{}) []clarity.DeploymentStage {
+	stages := make([]clarity.DeploymentStage, 0, len(in))
+	for _, s := range in {
+		if s == nil {
+			continue
+		}
+		m := s.(map[string]interface{})
+		stages = append(stages, clarity.DeploymentStage{
+			Traffic:  m["traffic_percentage"].(int),
+			BakeTime: durationSeconds(m["bake_duration"].(string)),
+			Advance:  m["advance"].(string),
+		})
+	}
+	return stages
+}
+
+func flattenStages(stages []clarity.DeploymentStage) []interface{} {
+	out := make([]interface{}, 0, len(stages))
+	for _, s := range stages {
+		out = append(out, map[string]interface{}{
+			"traffic_percentage": s.Traffic,
+			"bake_duration":      formatSeconds(s.BakeTime),
+			"advance":            s.Advance,
+		})
+	}
+	return out
+}
+
+func expandHealthChecks(in []interface{}) []clarity.HealthCheck {
+	checks := make([]clarity.HealthCheck, 0, len(in))
+	for _, c := range in {
+		if c == nil {
+			continue
+		}
+		m := c.(map[string]interface{})
+		check := clarity.HealthCheck{
+			Period: durationSeconds(m["evaluation_period"].(string)),
+		}
+		if alarm := m["cloudwatch_alarm"].(string); alarm != "" {
+			check.Type = clarity.HealthCheckTypeAlarm
+			check.Alarm = alarm
+		}
+		if url := m["http_url"].(string); url != "" {
+			check.Type = clarity.HealthCheckTypeHTTP
+			check.URL = url
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func flattenHealthChecks(checks []clarity.HealthCheck) []interface{} {
+	out := make([]interface{}, 0, len(checks))
+	for _, c := range checks {
+		out = append(out, map[string]interface{}{
+			"cloudwatch_alarm":  c.Alarm,
+			"http_url":          c.URL,
+			"evaluation_period": formatSeconds(c.Period),
+		})
+	}
+	return out
+}
+
+// validateHealthChecks requires every check to reference exactly one of an
+// alarm or a URL.
+func validateHealthChecks(in []interface{}) error {
+	for i, c := range in {
+		if c == nil {
+			continue
+		}
+		m := c.(map[string]interface{})
+		alarm := m["cloudwatch_alarm"].(string)
+		url := m["http_url"].(string)
+		if (alarm == "") == (url == "") {
+			return fmt.Errorf("deployment health_check %d: exactly one of cloudwatch_alarm or http_url must be specified", i+1)
+		}
+		if durationSeconds(m["evaluation_period"].(string)) <= 0 {
+			return fmt.Errorf("deployment health_check %d: evaluation_period must be greater than zero", i+1)
+		}
+	}
+	return nil
+}
+
+func expandEvaluation(in []interface{}) []clarity.EvaluationRule {
+	rules := make([]clarity.EvaluationRule, 0, len(in))
+	for _, r := range in {
+		if r == nil {
+			continue
+		}
+		m := r.(map[string]interface{})
+		rules = append(rules, clarity.EvaluationRule{
+			Metric:    m["metric"].(string),
+			Operator:  m["operator"].(string),
+			Threshold: m["threshold"].(float64),
+			Baseline:  m["compare_to_baseline"].(bool),
+		})
+	}
+	return rules
+}
+
+func flattenEvaluation(rules []clarity.EvaluationRule) []interface{} {
+	out := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, map[string]interface{}{
+			"metric":              r.Metric,
+			"operator":            r.Operator,
+			"threshold":           r.Threshold,
+			"compare_to_baseline": r.Baseline,
+		})
+	}
+	return out
+}
+
+// validateEvaluation checks thresholds are meaningful for their metric.
+func validateEvaluation(rules []clarity.EvaluationRule) error {
+	for i, r := range rules {
+		if r.Baseline {
+			continue
+		}
+
+		switch r.Metric {
+		case clarity.MetricErrorRate:
+			if r.Threshold > 100 {
+				return fmt.Errorf("deployment evaluation %d: error_rate threshold is a percentage and must be between 0 and 100", i+1)
+			}
+		case clarity.MetricLatencyP99:
+			if r.Threshold == 0 {
+				return fmt.Errorf("deployment evaluation %d: latency_p99 threshold must be greater than 0 milliseconds", i+1)
+			}
+		case clarity.MetricThrottles:
+			if r.Threshold != float64(int(r.Threshold)) {
+				return fmt.Errorf("deployment evaluation %d: throttles threshold is a count and must be a whole number", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// validateStages requires traffic to increase with every stage and the final
+// stage to shift all traffic.
+func validateStages(stages []clarity.DeploymentStage) error {
+	if len(stages) == 0 {
+		return nil
+	}
+
+	previous := 0
+	for i, s := range stages {
+		if s.Traffic <= previous {
+			return fmt.Errorf("deployment stage %d: traffic_percentage must increase with every stage, %d%% follows %d%%", i+1, s.Traffic, previous)
+		}
+		previous = s.Traffic
+	}
+
+	if previous != 100 {
+		return fmt.Errorf("deployment stages must end with traffic_percentage of 100, the final stage shifts %d%%", previous)
+	}
+
+	return nil
+}
+
+// The trigger blocks previously in state are used to preserve the legacy
+// manual_user_interface form, see flattenTriggers.
+func flattenDeployment(strategy clarity.DeploymentStrategy, priorTriggers []interface{}) []interface{} {
+	deployment := map[string]interface{}{}
+
+	if len(strategy.Trigger) > 0 {
+		deployment["trigger"] = flattenTriggers(strategy.Trigger, priorTriggers)
+	}
+
+	deployment["stage"] = flattenStages(strategy.Stages)
+	deployment["health_check"] = flattenHealthChecks(strategy.Health)
+	deployment["evaluation"] = flattenEvaluation(strategy.Evaluation)
+
+	return []interface{}{deployment}
+}
+
+// validateDeployment checks the deployment block at plan time.
+func validateDeployment(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("deployment") {
+		return nil
+	}
+
+	in := d.Get("deployment").([]interface{})
+	if len(in) == 0 || in[0] == nil {
+		return nil
+	}
+	deployment := in[0].(map[string]interface{})
+
+	if err := validateTriggers(deployment["trigger"].([]interface{})); err != nil {
+		return err
+	}
+
+	if err := validateStages(expandStages(deployment["stage"].([]interface{}))); err != nil {
+		return err
+	}
+
+	if err := validateHealthChecks(deployment["health_check"].([]interface{})); err != nil {
+		return err
+	}
+
+	if err := validateEvaluation(expandEvaluation(deployment["evaluation"].([]interface{}))); err != nil {
+		return err
+	}
+
+	return nil
+}
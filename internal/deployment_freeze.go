@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var resourceIDRegexp = regexp.MustCompile(`^[^/#]+/[^/#]+$`)
+
+func deploymentFreezeResource() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "A Clarity deployment freeze, blocking deployments to services and resources for a period of time.",
+
+		CreateContext: deploymentFreezeCreate,
+		ReadContext:   deploymentFreezeRead,
+		UpdateContext: deploymentFreezeUpdate,
+		DeleteContext: deploymentFreezeDelete,
+		CustomizeDiff: deploymentFreezeCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"start": {
+				Description:      "When the freeze starts, as an RFC3339 timestamp.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentTimestamp,
+			},
+			"end": {
+				Description:      "When the freeze ends, as an RFC3339 timestamp.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentTimestamp,
+			},
+			"reason": {
+				Description:  "Why deployments are frozen, shown to anyone attempting a deployment.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 512),
+			},
+			"service_slugs": {
+				Description:  "Slugs of the services frozen, covering all of their resources.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{"service_slugs", "resource_ids"},
+				Elem:         &schema.Schema{Type: schema.TypeString},
+			},
+			"resource_ids": {
+				Description:  "IDs of the resources frozen, in the form `<service slug>/<resource slug>`.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{"service_slugs", "resource_ids"},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(resourceIDRegexp, "must be of the form '<service slug>/<resource slug>'"),
+				},
+			},
+			"override_users": {
+				Description: "Users allowed to deploy during the freeze.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"idempotency_key": idempotencyKeySchema(),
+			"slug": {
+				Description: "A slug for this freeze.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func suppressEquivalentTimestamp(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	o, err := time.Parse(time.RFC3339, oldValue)
+	if err != nil {
+		return false
+	}
+	n, err := time.Parse(time.RFC3339, newValue)
+	if err != nil {
+		return false
+	}
+	return o.Equal(n)
+}
+
+func validateFreezeWindow(start, end string) error {
+	s, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return fmt.Errorf("Freeze 'start' must be an RFC3339 timestamp: %v", err)
+	}
+	e, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return fmt.Errorf("Freeze 'end' must be an RFC3339 timestamp: %v", err)
+	}
+	if !e.After(s) {
+		return fmt.Errorf("Freeze 'end' (%s) must be after 'start' (%s)", end, start)
+	}
+	return nil
+}
+
+func deploymentFreezeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := planIdempotencyKey(d); err != nil {
+		return err
+	}
+
+	if !d.NewValueKnown("start") || !d.NewValueKnown("end") {
+		return nil
+	}
+	return validateFreezeWindow(d.Get("start").(string), d.Get("end").(string))
+}
+
+func expandStringSet(in *schema.Set) []string {
+	out := make([]string, 0, in.Len())
+	for _, v := range in.List() {
+		out = append(out, v.(string))
+	}
+	return out
+}
+
+func expandDeploymentFreeze(d *schema.ResourceData) (clarity.FreezeRequest, error) {
+	req := clarity.FreezeRequest{
+		Start:         d.Get("start").(string),
+		End:           d.Get("end").(string),
+		Reason:        d.Get("reason").(string),
+		Services:      expandStringSet(d.Get("service_slugs").(*schema.Set)),
+		Resources:     []clarity.FreezeResource{},
+		OverrideUsers: expandStringSet(d.Get("override_users").(*schema.Set)),
+	}
+
+	for _, id := range expandStringSet(d.Get("resource_ids").(*schema.Set)) {
+		service, resource, err := splitID(id)
+		if err != nil {
+			return req, err
+		}
+		req.Resources = append(req.Resources, clarity.FreezeResource{
+			Service:  service,
+			Resource: resource,
+		})
+	}
+
+	return req, nil
+}
+
+func flattenFreezeResources(in []clarity.FreezeResource) []interface{} {
+	out := make([]interface{}, 0, len(in))
+	for _, r := range in {
+		out = append(out, renderID(r.Service, r.Resource))
+	}
+	return out
+}
+
+func deploymentFreezeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clarity.Client)
+
+	req, err := expandDeploymentFreeze(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	key := idempotencyKey(d)
+	var freeze *clarity.Freeze
+	err = createWithRetry(ctx, d.Timeout(schema.TimeoutCreate), func() (err error) {
+		freeze, err = client.CreateFreeze(req, key)
+		return err
+	})
+	if err != nil {
+		return diag.Errorf("creating deployment freeze: %v", err)
+	}
+
+	d.SetId(freeze.Slug)
+
+	tflog.Trace(ctx, "created a new deployment freeze")
+
+	return deploymentFreezeRead(ctx, d, meta)
+}
+
+func deploymentFreezeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clarity.Client)
+	slug := d.Id()
+
+	freeze, err := client.ReadFreeze(slug)
+	if err != nil {
+		if errors.Is(err, clarity.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("start", freeze.Start)
+	d.Set("end", freeze.End)
+	d.Set("reason", freeze.Reason)
+	d.Set("service_slugs", freeze.Services)
+	d.Set("resource_ids", flattenFreezeResources(freeze.Resources))
+	d.Set("override_users", freeze.OverrideUsers)
+	d.Set("slug", freeze.Slug)
+
+	return nil
+}
+
+func deploymentFreezeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clarity.Client)
+
+	req, err := expandDeploymentFreeze(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := client.UpdateFreeze(d.Id(), req); err != nil {
+		return diag.Errorf("updating deployment freeze: %v", err)
+	}
+
+	return deploymentFreezeRead(ctx, d, meta)
+}
+
+func deploymentFreezeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clarity.Client)
+
+	err := client.DeleteFreeze(d.Id())
+	if errors.Is(err, clarity.ErrNotFound) {
+		return nil
+	}
+	return diag.FromErr(err)
+}
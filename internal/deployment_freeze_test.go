@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDeploymentFreeze(t *testing.T) {
+	config := testAccProvider() + testAccService + testAccResource
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config + testAccDeploymentFreeze,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "reason", "Terraform test freeze"),
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "service_slugs.#", "1"),
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "resource_ids.#", "0"),
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "override_users.#", "1"),
+					resource.TestCheckResourceAttrSet("clarity_deployment_freeze.test", "slug"),
+				),
+			},
+			{
+				ResourceName:            "clarity_deployment_freeze.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"idempotency_key"},
+			},
+			{
+				Config: config + testAccDeploymentFreezeResource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "reason", "Terraform test resource freeze"),
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "service_slugs.#", "0"),
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "resource_ids.#", "1"),
+					resource.TestCheckResourceAttr("clarity_deployment_freeze.test", "override_users.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDeploymentFreeze = `
+resource "clarity_deployment_freeze" "test" {
+  start = "2030-12-20T00:00:00Z"
+  end = "2031-01-03T00:00:00Z"
+  reason = "Terraform test freeze"
+
+  service_slugs = [clarity_service.test.slug]
+  override_users = ["terraform-test@clarity.st"]
+}
+`
+
+const testAccDeploymentFreezeResource = `
+resource "clarity_deployment_freeze" "test" {
+  start = "2030-12-20T00:00:00Z"
+  end = "2031-01-03T00:00:00Z"
+  reason = "Terraform test resource freeze"
+
+  resource_ids = [clarity_resource.test.id]
+}
+`
+
+func TestValidateFreezeWindow(t *testing.T) {
+	if err := validateFreezeWindow("2030-12-20T00:00:00Z", "2030-12-20T01:00:00+00:30"); err != nil {
+		t.Errorf("expected window to be valid: %v", err)
+	}
+
+	cases := [][2]string{
+		{"2030-12-20T00:00:00Z", "2030-12-20T00:00:00Z"},
+		{"2030-12-20T00:00:00Z", "2030-12-19T00:00:00Z"},
+		{"2030-12-20T01:00:00Z", "2030-12-20T01:30:00+01:00"},
+		{"2030-12-20", "2030-12-21T00:00:00Z"},
+	}
+	for _, c := range cases {
+		if err := validateFreezeWindow(c[0], c[1]); err == nil {
+			t.Errorf("expected an error for start '%s' and end '%s'", c[0], c[1])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+)
+
+func TestValidateStages(t *testing.T) {
+	valid := [][]clarity.DeploymentStage{
+		nil,
+		{{Traffic: 100}},
+		{{Traffic: 10, BakeTime: 600}, {Traffic: 50}, {Traffic: 100}},
+	}
+	for _, stages := range valid {
+		if err := validateStages(stages); err != nil {
+			t.Errorf("expected %v to be valid: %v", stages, err)
+		}
+	}
+
+	invalid := [][]clarity.DeploymentStage{
+		{{Traffic: 50}},
+		{{Traffic: 50}, {Traffic: 10}, {Traffic: 100}},
+		{{Traffic: 50}, {Traffic: 50}, {Traffic: 100}},
+		{{Traffic: 100}, {Traffic: 100}},
+	}
+	for _, stages := range invalid {
+		if err := validateStages(stages); err == nil {
+			t.Errorf("expected %v to be invalid", stages)
+		}
+	}
+}
+
+func TestValidateHealthChecks(t *testing.T) {
+	check := func(alarm, url, period string) map[string]interface{} {
+		return map[string]interface{}{
+			"cloudwatch_alarm":  alarm,
+			"http_url":          url,
+			"evaluation_period": period,
+		}
+	}
+
+	valid := []interface{}{
+		check("api-errors", "", "5m"),
+		check("", "https://example.com/health", "30s"),
+	}
+	if err := validateHealthChecks(valid); err != nil {
+		t.Errorf("expected health checks to be valid: %v", err)
+	}
+
+	for _, c := range []map[string]interface{}{
+		check("", "", "5m"),
+		check("api-errors", "https://example.com/health", "5m"),
+		check("api-errors", "", "0s"),
+	} {
+		if err := validateHealthChecks([]interface{}{c}); err == nil {
+			t.Errorf("expected %v to be invalid", c)
+		}
+	}
+}
+
+func TestValidateEvaluation(t *testing.T) {
+	valid := []clarity.EvaluationRule{
+		{Metric: clarity.MetricErrorRate, Operator: clarity.OperatorGreaterThan, Threshold: 1.5},
+		{Metric: clarity.MetricLatencyP99, Operator: clarity.OperatorGreaterThan, Threshold: 250},
+		{Metric: clarity.MetricThrottles, Operator: clarity.OperatorGreaterThanOrEqual, Threshold: 10},
+		{Metric: clarity.MetricErrorRate, Operator: clarity.OperatorGreaterThan, Threshold: 200, Baseline: true},
+	}
+	if err := validateEvaluation(valid); err != nil {
+		t.Errorf("expected evaluation rules to be valid: %v", err)
+	}
+
+	for _, r := range []clarity.EvaluationRule{
+		{Metric: clarity.MetricErrorRate, Operator: clarity.OperatorGreaterThan, Threshold: 150},
+		{Metric: clarity.MetricLatencyP99, Operator: clarity.OperatorGreaterThan, Threshold: 0},
+		{Metric: clarity.MetricThrottles, Operator: clarity.OperatorGreaterThan, Threshold: 0.5},
+	} {
+		if err := validateEvaluation([]clarity.EvaluationRule{r}); err == nil {
+			t.Errorf("expected %v to be invalid", r)
+		}
+	}
+}
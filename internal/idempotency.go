@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// idempotencyKeySchema holds the key of the create request. It is generated
+// when planning the create, so every attempt made within one apply sends the
+// same key.
+func idempotencyKeySchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Idempotency key sent with every attempt to create this object.",
+		Type:        schema.TypeString,
+		Computed:    true,
+	}
+}
+
+// planIdempotencyKey plans a new key whenever a create is planned. A create
+// which fails leaves no state behind, so planning it again after a failed
+// apply gives a new key, see adoptExistingSchema for recovering from that.
+func planIdempotencyKey(d *schema.ResourceDiff) error {
+	if d.Id() != "" {
+		return nil
+	}
+	return d.SetNew("idempotency_key", clarity.NewIdempotencyKey())
+}
+
+// idempotencyKey returns the key planned for the create.
+func idempotencyKey(d *schema.ResourceData) string {
+	if key := d.Get("idempotency_key").(string); key != "" {
+		return key
+	}
+	return clarity.NewIdempotencyKey()
+}
+
+// createWithRetry repeats a create while its outcome is unknown, the caller
+// sends the same idempotency key on every attempt so the server only creates
+// the object once.
+func createWithRetry(ctx context.Context, timeout time.Duration, create func() error) error {
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		err := create()
+		if errors.Is(err, clarity.ErrUnavailable) {
+			tflog.Debug(ctx, "retrying create", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+}
+
+// adoptExistingSchema opts in to adopting an object with the same name and
+// configuration when creating, rather than failing with a conflict. A create
+// which timed out may still have succeeded server side, and the retry in a
+// later apply sends a new idempotency key.
+func adoptExistingSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Adopt an existing object with the same name and configuration when creating, such as one left behind by a create which timed out, rather than failing with a conflict. The adopted object is then managed, and destroyed, by this configuration. Only enable it to recover a failed create, two configurations adopting the same object would both manage it and either could delete it.",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+func adoptedWarning(kind string, name string, slug string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Adopted existing %s '%s'", kind, name),
+		Detail:   fmt.Sprintf("A %s with the same name and configuration already exists (slug '%s'), most likely created by a previous attempt which timed out. It is now managed by this configuration.", kind, slug),
+	}
+}
+
+func sameProviderInfo(a clarity.ProviderInfo, b clarity.ProviderInfo) bool {
+	if a.Type != b.Type {
+		return false
+	}
+
+	switch {
+	case a.AWS != nil && b.AWS != nil:
+		return a.AWS.AccountID == b.AWS.AccountID &&
+			a.AWS.Role == b.AWS.Role &&
+			a.AWS.Region == b.AWS.Region &&
+			sameOptionalString(a.AWS.AdditionalAccountID, b.AWS.AdditionalAccountID) &&
+			sameOptionalString(a.AWS.ExternalID, b.AWS.ExternalID) &&
+			sameStrings(a.AWS.AdditionalAccountIDs, b.AWS.AdditionalAccountIDs)
+	case a.Webhook != nil && b.Webhook != nil:
+		return a.Webhook.URL == b.Webhook.URL
+	case a.GCP != nil && b.GCP != nil:
+		return a.GCP.Project == b.GCP.Project
+	default:
+		return false
+	}
+}
+
+// sameOptionalString treats an unset value as equal to an empty one.
+func sameOptionalString(a *string, b *string) bool {
+	var av, bv string
+	if a != nil {
+		av = *a
+	}
+	if b != nil {
+		bv = *b
+	}
+	return av == bv
+}
+
+// sameStrings treats a nil slice as equal to an empty one.
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameServiceMetadata treats nil links as equal to an empty list.
+func sameServiceMetadata(a clarity.ServiceMetadata, b clarity.ServiceMetadata) bool {
+	if a.Description != b.Description ||
+		a.OwnerTeam != b.OwnerTeam ||
+		a.RepositoryURL != b.RepositoryURL ||
+		len(a.Links) != len(b.Links) {
+		return false
+	}
+	for i := range a.Links {
+		if a.Links[i] != b.Links[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameResource reports whether an existing resource matches every field of
+// the create request.
+func sameResource(existing *clarity.InternalResource, req clarity.CreateResourceRequest) bool {
+	return existing.Name == req.Name &&
+		existing.Provider == req.Provider &&
+		existing.PromoteFrom == req.PromoteFrom &&
+		sameConfiguration(existing.Data, req.Configuration)
+}
+
+// sameConfiguration compares resource configurations ignoring create-only
+// values the server does not report back.
+func sameConfiguration(a clarity.Configuration, b clarity.Configuration) bool {
+	if a.LambdaConfiguration != nil && b.LambdaConfiguration != nil {
+		return a.Type == b.Type &&
+			a.LambdaConfiguration.Name == b.LambdaConfiguration.Name &&
+			a.LambdaConfiguration.Alias == b.LambdaConfiguration.Alias
+	}
+	return reflect.DeepEqual(a, b)
+}
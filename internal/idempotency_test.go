@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+)
+
+func TestSameProviderInfo(t *testing.T) {
+	aws := func(mutate func(*clarity.AWS)) clarity.ProviderInfo {
+		info := &clarity.AWS{
+			AccountID: "123456789012",
+			Role:      "deploy",
+			Region:    "us-east-1",
+		}
+		mutate(info)
+		return clarity.ProviderInfo{TypeSwitch: clarity.AWSProviderType, AWS: info}
+	}
+	external := "external"
+	empty := ""
+
+	if !sameProviderInfo(aws(func(*clarity.AWS) {}), aws(func(a *clarity.AWS) { a.ExternalID = &empty })) {
+		t.Errorf("expected an empty external_id to match an unset one")
+	}
+
+	cases := []func(*clarity.AWS){
+		func(a *clarity.AWS) { a.Role = "other" },
+		func(a *clarity.AWS) { a.ExternalID = &external },
+		func(a *clarity.AWS) { a.AdditionalAccountID = &external },
+		func(a *clarity.AWS) { a.AdditionalAccountIDs = []string{"210987654321"} },
+	}
+	for i, c := range cases {
+		if sameProviderInfo(aws(func(*clarity.AWS) {}), aws(c)) {
+			t.Errorf("case %d: expected providers to differ", i)
+		}
+	}
+}
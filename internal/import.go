@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// importNamePrefix selects import by name rather than slug, e.g. `name:My API`.
+const importNamePrefix = "name:"
+
+func findProviderByName(providers []clarity.Provider, name string) (*clarity.Provider, error) {
+	var matches []clarity.Provider
+	for _, p := range providers {
+		if p.Name == name {
+			matches = append(matches, p)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("No matching provider found with the name '%s'", name)
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("Found multiple providers with the name '%s'", name)
+	}
+	return &matches[0], nil
+}
+
+func findServiceByName(services []clarity.Service, name string) (*clarity.Service, error) {
+	var matches []clarity.Service
+	for _, s := range services {
+		if s.Name == name {
+			matches = append(matches, s)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("No matching service found with the name '%s'", name)
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("Found multiple service with the name '%s'", name)
+	}
+	return &matches[0], nil
+}
+
+func findResourceByName(service clarity.Service, name string) (*clarity.Resource, error) {
+	var matches []clarity.Resource
+	for _, r := range service.Resources {
+		if r.Name == name {
+			matches = append(matches, r)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("No matching resource found with the name '%s' on service '%s'", name, service.Name)
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("Found multiple resources with the name '%s' on service '%s'", name, service.Name)
+	}
+	return &matches[0], nil
+}
+
+// Accepts either a provider slug or `name:<provider name>`.
+func providerImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*clarity.Client)
+
+	if name := strings.TrimPrefix(d.Id(), importNamePrefix); name != d.Id() {
+		providers, err := client.LoadProviders()
+		if err != nil {
+			return nil, err
+		}
+
+		provider, err := findProviderByName(providers, name)
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(provider.Slug)
+	}
+
+	d.Set("force_destroy", false)
+	d.Set("adopt_existing", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// Accepts either a service slug or `name:<service name>`.
+func serviceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*clarity.Client)
+
+	if name := strings.TrimPrefix(d.Id(), importNamePrefix); name != d.Id() {
+		rsp, err := client.ListServices()
+		if err != nil {
+			return nil, err
+		}
+
+		service, err := findServiceByName(rsp.Services, name)
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(service.Slug)
+	}
+
+	d.Set("force_destroy", false)
+	d.Set("adopt_existing", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// Accepts either `<service slug>/<resource slug>` or
+// `<service name>/<resource name>`, slugs take precedence. A service name
+// containing '/' must be path escaped, e.g. `My%2FAPI/dev`. The legacy
+// `<service slug>#<resource slug>` form is also supported.
+func resourceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*clarity.Client)
+
+	first, second, err := splitID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected import ID '%s', expected '<service slug>/<resource slug>' or '<service name>/<resource name>'", d.Id())
+	}
+	d.Set("adopt_existing", false)
+
+	if !strings.Contains(d.Id(), idSeparator) {
+		d.SetId(renderID(first, second))
+		return []*schema.ResourceData{d}, nil
+	}
+
+	_, err = client.ReadResource(first, second)
+	if err == nil {
+		return []*schema.ResourceData{d}, nil
+	}
+	if !errors.Is(err, clarity.ErrNotFound) {
+		return nil, err
+	}
+
+	serviceName, err := url.PathUnescape(first)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected service name '%s': %v", first, err)
+	}
+	resourceName, err := url.PathUnescape(second)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected resource name '%s': %v", second, err)
+	}
+
+	rsp, err := client.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := findServiceByName(rsp.Services, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := findResourceByName(*service, resourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(renderID(service.Slug, resource.Slug))
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// promotionCycle follows the promote_from references, looked up with
+// promoteFrom, starting at 'from' and returns the chain if it leads back to
+// 'slug', or nil.
+func promotionCycle(promoteFrom func(slug string) (string, error), slug string, from string) ([]string, error) {
+	chain := []string{slug}
+	seen := map[string]bool{slug: true}
+	for current := from; current != ""; {
+		chain = append(chain, current)
+		if current == slug {
+			return chain, nil
+		}
+		// A cycle not including slug predates this change, the server is
+		// responsible for it.
+		if seen[current] {
+			return nil, nil
+		}
+		seen[current] = true
+
+		next, err := promoteFrom(current)
+		if err != nil {
+			return nil, err
+		}
+		current = next
+	}
+	return nil, nil
+}
+
+// checkPromotion checks 'from' is a resource in the same service, and that
+// promoting 'slug' from it does not loop back on itself. Only the resources
+// along the promotion chain are read. A new resource has no slug yet, and is
+// not referenced by any other so can not be part of a cycle.
+func checkPromotion(api *clarity.Client, serviceSlug string, slug string, from string) error {
+	if from == "" {
+		return nil
+	}
+	if from == slug {
+		return fmt.Errorf("Resource '%s' can not be promoted from itself", slug)
+	}
+
+	internal, err := api.ReadResource(serviceSlug, from)
+	if err != nil {
+		if errors.Is(err, clarity.ErrNotFound) {
+			return fmt.Errorf("Unable to find resource with slug '%s' to promote from in service '%s'", from, serviceSlug)
+		}
+		return fmt.Errorf("loading resource '%s' for validation: %v", from, err)
+	}
+
+	if slug == "" {
+		return nil
+	}
+
+	next := map[string]string{from: internal.PromoteFrom}
+	chain, err := promotionCycle(func(current string) (string, error) {
+		if v, ok := next[current]; ok {
+			return v, nil
+		}
+
+		internal, err := api.ReadResource(serviceSlug, current)
+		if err != nil {
+			// A dangling reference ends the chain.
+			if errors.Is(err, clarity.ErrNotFound) {
+				return "", nil
+			}
+			return "", fmt.Errorf("loading resource '%s' for validation: %v", current, err)
+		}
+		return internal.PromoteFrom, nil
+	}, slug, from)
+	if err != nil {
+		return err
+	}
+	if chain != nil {
+		return fmt.Errorf("Promotion cycle detected: %s", strings.Join(chain, " <- "))
+	}
+
+	return nil
+}
+
+// validatePromotion checks promote_from at plan time against the references
+// the server currently holds. Resources whose promote_from change together in
+// one plan are each checked against the others' current values, a cycle
+// between them is caught when the update is applied.
+func validatePromotion(api *clarity.Client, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("promote_from") || !d.NewValueKnown("service_slug") {
+		return nil
+	}
+
+	var slug string
+	if d.Id() != "" {
+		slug = d.Get("slug").(string)
+	}
+
+	return checkPromotion(api, d.Get("service_slug").(string), slug, d.Get("promote_from").(string))
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestPromotionCycle(t *testing.T) {
+	promoteFrom := map[string]string{
+		"dev":     "",
+		"staging": "dev",
+		"prod":    "staging",
+		"a":       "b",
+		"b":       "a",
+	}
+	lookup := func(slug string) (string, error) {
+		return promoteFrom[slug], nil
+	}
+
+	if chain, _ := promotionCycle(lookup, "dev", "prod"); !reflect.DeepEqual([]string{"dev", "prod", "staging", "dev"}, chain) {
+		t.Errorf("unexpected chain %v", chain)
+	}
+
+	if chain, _ := promotionCycle(lookup, "prod", "staging"); chain != nil {
+		t.Errorf("expected no cycle, got %v", chain)
+	}
+
+	// An existing cycle elsewhere in the service is not attributed to this resource.
+	if chain, _ := promotionCycle(lookup, "prod", "a"); chain != nil {
+		t.Errorf("expected no cycle, got %v", chain)
+	}
+}
+
+// promotionServer serves the resources of the service 'service', keyed by slug
+// with the slug they promote from, recording every request made.
+func promotionServer(promoteFrom map[string]string, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+
+		slug := strings.TrimPrefix(r.URL.Path, "/service/service/resource/")
+		from, ok := promoteFrom[slug]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			var req clarity.UpdateResourceRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			from = req.PromoteFrom
+			promoteFrom[slug] = from
+		}
+
+		fmt.Fprintf(w, `{"name": %q, "slug": %q, "provider": "provider", "data": {"type": "aws", "configuration": {"name": "function", "alias": "clarity"}}, "promote_from": %q}`, slug, slug, from)
+	}))
+}
+
+func TestCheckPromotion(t *testing.T) {
+	cases := []struct {
+		slug     string
+		from     string
+		err      string
+		requests []string
+	}{
+		{"prod", "", "", nil},
+		{"", "staging", "", []string{"GET /service/service/resource/staging"}},
+		{"prod", "prod", "Resource 'prod' can not be promoted from itself", nil},
+		{"prod", "missing", "Unable to find resource with slug 'missing' to promote from in service 'service'", []string{"GET /service/service/resource/missing"}},
+		{"prod", "staging", "", []string{"GET /service/service/resource/staging", "GET /service/service/resource/dev"}},
+		{"dev", "prod", "Promotion cycle detected: dev <- prod <- staging <- dev", []string{"GET /service/service/resource/prod", "GET /service/service/resource/staging"}},
+	}
+
+	for _, c := range cases {
+		var requests []string
+		server := promotionServer(map[string]string{
+			"dev":       "",
+			"staging":   "dev",
+			"prod":      "staging",
+			"unrelated": "",
+		}, &requests)
+		api := &clarity.Client{Host: server.URL, Client: server.Client()}
+
+		err := checkPromotion(api, "service", c.slug, c.from)
+		server.Close()
+
+		if c.err == "" && err != nil {
+			t.Errorf("checkPromotion(%q, %q) unexpected error: %v", c.slug, c.from, err)
+		}
+		if c.err != "" && (err == nil || err.Error() != c.err) {
+			t.Errorf("checkPromotion(%q, %q) = %v, expected %q", c.slug, c.from, err, c.err)
+		}
+		if !reflect.DeepEqual(c.requests, requests) {
+			t.Errorf("checkPromotion(%q, %q) requests = %v, expected %v", c.slug, c.from, requests, c.requests)
+		}
+	}
+}
+
+func promotionResourceData(t *testing.T, promoteFrom string) *schema.ResourceData {
+	state := &terraform.InstanceState{
+		ID: "service/a",
+		Attributes: map[string]string{
+			"id":                     "service/a",
+			"provider_slug":          "provider",
+			"service_slug":           "service",
+			"name":                   "a",
+			"lambda.#":               "1",
+			"lambda.0.function_name": "function",
+			"lambda.0.alias":         "clarity",
+			"promote_from":           "",
+			"slug":                   "a",
+		},
+	}
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"promote_from": {Old: "", New: promoteFrom},
+		},
+	}
+
+	d, err := schema.InternalMap(resourceResource().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("error building resource data: %s", err)
+	}
+	return d
+}
+
+func TestResourceUpdatePromoteFrom(t *testing.T) {
+	var requests []string
+	promoteFrom := map[string]string{"a": "", "b": ""}
+	server := promotionServer(promoteFrom, &requests)
+	defer server.Close()
+	api := &clarity.Client{Host: server.URL, Client: server.Client()}
+
+	d := promotionResourceData(t, "b")
+	if diags := resourceUpdate(context.Background(), d, api); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if promoteFrom["a"] != "b" {
+		t.Errorf("expected promote_from to be sent, server has %q", promoteFrom["a"])
+	}
+	if v := d.Get("promote_from"); v != "b" {
+		t.Errorf("expected promote_from 'b' in state, got %q", v)
+	}
+}
+
+// A cycle formed by another resource applied earlier in the same plan is
+// caught at apply time.
+func TestResourceUpdatePromoteFromCycle(t *testing.T) {
+	var requests []string
+	promoteFrom := map[string]string{"a": "", "b": "a"}
+	server := promotionServer(promoteFrom, &requests)
+	defer server.Close()
+	api := &clarity.Client{Host: server.URL, Client: server.Client()}
+
+	diags := resourceUpdate(context.Background(), promotionResourceData(t, "b"), api)
+	if !diags.HasError() || diags[0].Summary != "Promotion cycle detected: a <- b <- a" {
+		t.Fatalf("expected a promotion cycle error, got %v", diags)
+	}
+
+	for _, r := range requests {
+		if strings.HasPrefix(r, http.MethodPost) {
+			t.Errorf("expected no update to be sent, got %q", r)
+		}
+	}
+}
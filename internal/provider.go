@@ -48,9 +48,10 @@ func New(version string) func() *schema.Provider {
 				"clarity_service":  serviceDatasource(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
-				"clarity_service":  serviceResource(),
-				"clarity_resource": resourceResource(),
-				"clarity_provider": providerResource(),
+				"clarity_service":           serviceResource(),
+				"clarity_resource":          resourceResource(),
+				"clarity_provider":          providerResource(),
+				"clarity_deployment_freeze": deploymentFreezeResource(),
 			},
 		}
 
@@ -1,11 +1,12 @@
 package internal
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -14,15 +15,54 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// Resource IDs are composed of the service and resource slugs. The canonical
+// separator is '/', '#' is accepted for IDs created by earlier versions.
+const idSeparator = "/"
+const legacyIDSeparator = "#"
+
 func renderID(service, resource string) string {
-	return fmt.Sprintf("%s#%s", service, resource)
+	return fmt.Sprintf("%s%s%s", service, idSeparator, resource)
+}
+
+// splitID splits on the first separator only, slugs never contain one but
+// names used when importing may. The legacy separator is only used when the
+// canonical one is absent.
+func splitID(input string) (string, string, error) {
+	sep := idSeparator
+	if !strings.Contains(input, idSeparator) {
+		sep = legacyIDSeparator
+	}
+
+	out := strings.SplitN(input, sep, 2)
+	if len(out) != 2 || out[0] == "" || out[1] == "" {
+		return "", "", fmt.Errorf("Unexpected resource ID '%s', expected '<service slug>/<resource slug>'", input)
+	}
+	return out[0], out[1], nil
+}
+
+func parseID(input string) (string, string, diag.Diagnostics) {
+	service, resource, err := splitID(input)
+	if err != nil {
+		return "", "", diag.FromErr(err)
+	}
+	return service, resource, nil
 }
 
-func parseID(input string) (string, string) {
-	out := strings.Split(input, "#")
-	service := out[0]
-	resource := out[1]
-	return service, resource
+// resourceTypeBlocks are the configuration blocks of the supported resource
+// types, exactly one of which must be set.
+var resourceTypeBlocks = []string{"lambda", "ecs", "kubernetes_deployment", "cloud_run"}
+
+var lambdaVersionRegexp = regexp.MustCompile(`^[1-9][0-9]*$`)
+var gcpProjectRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+var gcpRegionRegexp = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+var kubernetesNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+var kubernetesSubdomainRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+func validateKubernetesName() schema.SchemaValidateFunc {
+	return validation.All(
+		validation.StringLenBetween(1, 63),
+		validation.StringMatch(kubernetesNameRegexp, "must be a lowercase RFC 1123 label"),
+	)
 }
 
 func resourceResource() *schema.Resource {
@@ -30,12 +70,25 @@ func resourceResource() *schema.Resource {
 		// This description is used by the documentation generator and the language server.
 		Description: "A Clarity resource.",
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceResourceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceStateUpgradeV0,
+			},
+		},
+
 		CreateContext: resourceCreate,
 		ReadContext:   resourceRead,
 		UpdateContext: resourceUpdate,
 		DeleteContext: resourceDelete,
+		CustomizeDiff: resourceCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -54,16 +107,18 @@ func resourceResource() *schema.Resource {
 			"name": {
 				Description: "A name for the resource.",
 				Type:        schema.TypeString,
-				ForceNew:    true,
 				Required:    true,
 			},
+			// A list rather than a set: a set element is replaced whenever any
+			// of its values change, which would force a new resource through
+			// function_name whenever the alias is updated in place.
 			"lambda": {
-				Description: "AWS Lambda import configuration.",
-				Type:        schema.TypeSet,
-				MaxItems:    1,
-				MinItems:    1,
-				ForceNew:    true,
-				Required:    true,
+				Description:  "AWS Lambda import configuration.",
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				MinItems:     1,
+				Optional:     true,
+				ExactlyOneOf: resourceTypeBlocks,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"function_name": {
@@ -74,45 +129,154 @@ func resourceResource() *schema.Resource {
 							ValidateFunc: validation.StringLenBetween(0, 255),
 						},
 						"alias": {
-							Description: "AWS Lambda alias",
+							Description: "AWS Lambda alias. Changing an alias created with `create_alias` replaces the resource.",
 							Type:        schema.TypeString,
-							ForceNew:    true,
 							Optional:    true,
 							Default:     "clarity",
 						},
+						"create_alias": {
+							Description:      "Have Clarity create the alias rather than importing an existing one. Only used when creating the resource.",
+							Type:             schema.TypeBool,
+							Optional:         true,
+							Default:          false,
+							DiffSuppressFunc: suppressAfterCreate,
+						},
+						"version": {
+							Description:      "Published function version the created alias points at. Defaults to publishing a version from `$LATEST`. Only valid with `create_alias`, and only used when creating the resource.",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validation.StringMatch(lambdaVersionRegexp, "must be a published version number"),
+							DiffSuppressFunc: suppressAfterCreate,
+						},
 					},
 				},
 			},
-			"deployment": {
-				Description: "Deployment configuration.",
-				Type:        schema.TypeSet,
-				MaxItems:    1,
-				Optional:    true,
+			"ecs": {
+				Description:  "Amazon ECS service import configuration, including services running on Fargate.",
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				MinItems:     1,
+				ForceNew:     true,
+				Optional:     true,
+				ExactlyOneOf: resourceTypeBlocks,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cluster": {
+							Description:  "ECS cluster name or ARN",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 255),
+						},
+						"service_name": {
+							Description:  "ECS service name",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 255),
+						},
+						"container_name": {
+							Description:  "Name of the container in the task definition to deploy",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 255),
+						},
+					},
+				},
+			},
+			"kubernetes_deployment": {
+				Description:  "Kubernetes Deployment import configuration, the cluster is reached through the provider.",
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				MinItems:     1,
+				ForceNew:     true,
+				Optional:     true,
+				ExactlyOneOf: resourceTypeBlocks,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"namespace": {
+							Description:  "Kubernetes namespace",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Optional:     true,
+							Default:      "default",
+							ValidateFunc: validateKubernetesName(),
+						},
+						"deployment_name": {
+							Description: "Kubernetes Deployment name",
+							Type:        schema.TypeString,
+							ForceNew:    true,
+							Required:    true,
+							ValidateFunc: validation.All(
+								validation.StringLenBetween(1, 253),
+								validation.StringMatch(kubernetesSubdomainRegexp, "must be a lowercase RFC 1123 subdomain"),
+							),
+						},
+						"container": {
+							Description:  "Name of the container in the pod template to deploy",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Required:     true,
+							ValidateFunc: validateKubernetesName(),
+						},
+					},
+				},
+			},
+			"cloud_run": {
+				Description:  "Google Cloud Run service import configuration.",
+				Type:         schema.TypeList,
+				MaxItems:     1,
+				MinItems:     1,
+				ForceNew:     true,
+				Optional:     true,
+				ExactlyOneOf: resourceTypeBlocks,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"trigger": {
-							Description: "Deployment trigger configuration",
-							Type:        schema.TypeSet,
-							MaxItems:    1,
+						"project": {
+							Description:  "GCP project ID",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(gcpProjectRegexp, "must be a GCP project ID"),
+						},
+						"region": {
+							Description:  "GCP region, e.g. `europe-west1`",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(gcpRegionRegexp, "must be a GCP region"),
+						},
+						"service_name": {
+							Description: "Cloud Run service name",
+							Type:        schema.TypeString,
+							ForceNew:    true,
 							Required:    true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"manual_user_interface": {
-										Description: "Enable manual deployment lock, controlled via the user interface",
-										Type:        schema.TypeBool,
-										Optional:    true,
-										Default:     false,
-										DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
-											return oldValue == newValue
-										},
-									},
-								},
-							},
+							ValidateFunc: validation.All(
+								validation.StringLenBetween(1, 49),
+								validation.StringMatch(kubernetesNameRegexp, "must only contain lowercase alphanumeric characters and '-'"),
+							),
+						},
+						"traffic_tag": {
+							Description:  "Cloud Run traffic tag",
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Optional:     true,
+							Default:      "clarity",
+							ValidateFunc: validateKubernetesName(),
 						},
 					},
 				},
 			},
+			"deployment": deploymentSchema(),
+			"promote_from": {
+				Description: "Slug of a resource in the same service a version must have been deployed to successfully before it is eligible for this resource, e.g. staging before production.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
 
+			"adopt_existing":  adoptExistingSchema(),
+			"idempotency_key": idempotencyKeySchema(),
 			"slug": {
 				Description: "A slug for this resource.",
 				Type:        schema.TypeString,
@@ -123,6 +287,128 @@ func resourceResource() *schema.Resource {
 	}
 }
 
+// configuredResourceType returns the resource type selected by the configuration.
+func configuredResourceType(d *schema.ResourceDiff) string {
+	if v, ok := d.GetOk("ecs"); ok && len(v.([]interface{})) > 0 {
+		return clarity.ResourceTypeECS
+	}
+	if v, ok := d.GetOk("kubernetes_deployment"); ok && len(v.([]interface{})) > 0 {
+		return clarity.ResourceTypeKubernetes
+	}
+	if v, ok := d.GetOk("cloud_run"); ok && len(v.([]interface{})) > 0 {
+		return clarity.ResourceTypeCloudRun
+	}
+	return clarity.ResourceTypeLambda
+}
+
+// Reject invalid deployment strategies, resource types the provider is unable
+// to deploy or the service does not support, and promotion cycles, at plan
+// time rather than failing part way through an apply.
+func resourceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	api := meta.(*clarity.Client)
+	resourceType := configuredResourceType(d)
+
+	if err := planIdempotencyKey(d); err != nil {
+		return err
+	}
+
+	if err := validateDeployment(d); err != nil {
+		return err
+	}
+
+	if err := validateLambda(d.Get("lambda").([]interface{})); err != nil {
+		return err
+	}
+
+	// Clarity owns an alias it created, renaming it means creating another.
+	if d.Id() != "" && d.HasChange("lambda.0.alias") && d.Get("lambda.0.create_alias").(bool) {
+		if err := d.ForceNew("lambda.0.alias"); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() == "" || d.HasChange("provider_slug") || d.HasChanges(resourceTypeBlocks...) {
+		if err := validateProviderCapability(api, d, resourceType); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() == "" || d.HasChange("service_slug") || d.HasChanges(resourceTypeBlocks...) {
+		if err := validateServiceType(api, d, resourceType); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() == "" || d.HasChanges("service_slug", "promote_from") {
+		if err := validatePromotion(api, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateLambda(in []interface{}) error {
+	for _, l := range in {
+		m := l.(map[string]interface{})
+		if m["version"].(string) != "" && !m["create_alias"].(bool) {
+			return fmt.Errorf("Lambda 'version' is only valid with 'create_alias', an imported alias keeps the version it points at")
+		}
+	}
+	return nil
+}
+
+func validateProviderCapability(api *clarity.Client, d *schema.ResourceDiff, resourceType string) error {
+	if !d.NewValueKnown("provider_slug") {
+		return nil
+	}
+
+	providerSlug := d.Get("provider_slug").(string)
+	provider, err := api.LoadProvider(providerSlug)
+	if err != nil {
+		if errors.Is(err, clarity.ErrNotFound) {
+			return fmt.Errorf("Unable to find provider with slug '%s'", providerSlug)
+		}
+		return fmt.Errorf("loading provider '%s' for validation: %v", providerSlug, err)
+	}
+
+	if !provider.SupportsResourceType(resourceType) {
+		return fmt.Errorf("Provider '%s' of type '%s' is unable to deploy '%s' resources", providerSlug, provider.Info.Type, resourceType)
+	}
+
+	// Providers without reported capabilities predate the capability model.
+	if len(provider.Capabilities) == 0 {
+		return nil
+	}
+
+	if !provider.CanDeploy(resourceType) {
+		return fmt.Errorf("Provider '%s' is unable to deploy '%s' resources, supported types: %s", providerSlug, resourceType, strings.Join(provider.Capabilities, ", "))
+	}
+
+	return nil
+}
+
+func validateServiceType(api *clarity.Client, d *schema.ResourceDiff, resourceType string) error {
+	if !d.NewValueKnown("service_slug") {
+		return nil
+	}
+
+	serviceSlug := d.Get("service_slug").(string)
+	service, err := api.LoadService(serviceSlug)
+	if err != nil {
+		return fmt.Errorf("loading service '%s' for validation: %v", serviceSlug, err)
+	}
+	if service == nil {
+		return fmt.Errorf("Unable to find service with slug '%s'", serviceSlug)
+	}
+
+	if !service.SupportsResourceType(resourceType) {
+		return fmt.Errorf("Service '%s' of type '%s' does not support '%s' resources", serviceSlug, service.ServiceType, resourceType)
+	}
+
+	return nil
+}
+
 func resourceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	api := meta.(*clarity.Client)
 
@@ -130,12 +416,7 @@ func resourceCreate(ctx context.Context, d *schema.ResourceData, meta interface{
 	serviceSlug := d.Get("service_slug").(string)
 	name := d.Get("name").(string)
 
-	lambdaSchema := d.Get("lambda").(*schema.Set)
-	// Validation should enforce
-	lambdaSchema0 := lambdaSchema.List()[0]
-	lambda := lambdaSchema0.(map[string]interface{})
-	functionName := lambda["function_name"].(string)
-	alias := lambda["alias"].(string)
+	configuration := expandConfiguration(d)
 
 	// Validate
 	service, err := api.LoadService(serviceSlug)
@@ -145,78 +426,124 @@ func resourceCreate(ctx context.Context, d *schema.ResourceData, meta interface{
 	if service == nil {
 		return diag.Errorf("Unable to find service with slug '%s'", serviceSlug)
 	}
+	req := clarity.CreateResourceRequest{
+		Name:          name,
+		Provider:      providerSlug,
+		RequestType:   resourceRequestType(d),
+		Configuration: configuration,
+		PromoteFrom:   d.Get("promote_from").(string),
+	}
+
+	var diags diag.Diagnostics
+	var internal *clarity.InternalResource
 	for _, r := range service.Resources {
-		if r.Name == name {
+		if r.Name != name {
+			continue
+		}
+
+		if !d.Get("adopt_existing").(bool) {
+			return diag.Errorf("Conflict. Resource with the name '%s' already exists on the specified service", name)
+		}
+
+		existing, err := api.ReadResource(serviceSlug, r.Slug)
+		if err != nil {
+			return diag.Errorf("loading resource '%s' for validation: %v", r.Slug, err)
+		}
+		if !sameResource(existing, req) {
 			return diag.Errorf("Conflict. Resource with the name '%s' already exists on the specified service", name)
 		}
+
+		internal = existing
+		diags = append(diags, adoptedWarning("resource", name, r.Slug))
 	}
 
-	internal, err := api.CreateResource(serviceSlug, clarity.CreateResourceRequest{
-		Name:        name,
-		Provider:    providerSlug,
-		RequestType: "import",
-		Configuration: clarity.Configuration{
-			Type: "aws",
-			LambdaConfiguration: clarity.LambdaConfiguration{
-				Name:  functionName,
-				Alias: alias,
-			},
-		},
-	})
-	if err != nil {
-		tflog.Error(ctx, "error", map[string]interface{}{
-			"message": fmt.Sprintf("%v", err),
+	if internal == nil {
+		key := idempotencyKey(d)
+		err = createWithRetry(ctx, d.Timeout(schema.TimeoutCreate), func() (err error) {
+			internal, err = api.CreateResource(serviceSlug, req, key)
+			return err
 		})
-		return diag.FromErr(err)
+		if err != nil {
+			tflog.Error(ctx, "error", map[string]interface{}{
+				"message": fmt.Sprintf("%v", err),
+			})
+			return diag.FromErr(err)
+		}
 	}
 
 	d.SetId(renderID(serviceSlug, internal.Slug))
 
-	if v, ok := d.GetOk("deployment"); ok && len(v.(*schema.Set).List()) > 0 {
-		deploymentSchema := v.(*schema.Set).List()[0]
-		deployment := deploymentSchema.(map[string]interface{})
-		triggerSchema := deployment["trigger"].(*schema.Set).List()[0]
-		trigger := triggerSchema.(map[string]interface{})
-		userInterfaceTrigger := trigger["manual_user_interface"].(bool)
-		if userInterfaceTrigger {
-			if err := api.UpdateResourceDeploymentStrategy(serviceSlug, internal.Slug, internal.EnableUserInterfaceTrigger()); err != nil {
-				return diag.FromErr(err)
-			}
+	if v, ok := d.GetOk("deployment"); ok {
+		strategy := clarity.UpdateDeploymentStrategy{
+			Strategy: expandDeployment(v.([]interface{}), internal.Deployment),
+		}
+		if err := api.UpdateResourceDeploymentStrategy(serviceSlug, internal.Slug, strategy); err != nil {
+			return append(diags, diag.FromErr(err)...)
 		}
 	}
 
-	return resourceRead(ctx, d, meta)
+	return append(diags, resourceRead(ctx, d, meta)...)
 }
 
-// Limited functionality constrianed to deployment triggers.
+// The name, Lambda alias, promotion source and deployment strategy may change
+// in place, keeping the resource's deployment history.
 func resourceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	api := meta.(*clarity.Client)
-	serviceSlug, resourceSlug := parseID(d.Id())
+	serviceSlug, resourceSlug, diags := parseID(d.Id())
+	if diags.HasError() {
+		return diags
+	}
+
+	if d.HasChanges("name", "lambda", "promote_from") {
+		name := d.Get("name").(string)
+
+		if d.HasChange("name") {
+			service, err := api.LoadService(serviceSlug)
+			if err != nil {
+				return diag.Errorf("loading service '%s' to confirm uniqueness: %v", serviceSlug, err)
+			}
+			if service == nil {
+				return diag.Errorf("Unable to find service with slug '%s'", serviceSlug)
+			}
+
+			for _, r := range service.Resources {
+				if r.Name == name && r.Slug != resourceSlug {
+					return diag.Errorf("Conflict. Resource with the name '%s' already exists on the specified service", name)
+				}
+			}
+		}
 
-	if v, ok := d.GetOk("deployment"); ok && len(v.(*schema.Set).List()) > 0 {
-		deploymentSchema := v.(*schema.Set).List()[0]
-		deployment := deploymentSchema.(map[string]interface{})
-		triggerSchema := deployment["trigger"].(*schema.Set).List()[0]
-		trigger := triggerSchema.(map[string]interface{})
-		userInterfaceTrigger := trigger["manual_user_interface"].(bool)
+		// Checked again as other resources in the same plan may have changed
+		// their promote_from since this one was planned.
+		if d.HasChange("promote_from") {
+			if err := checkPromotion(api, serviceSlug, resourceSlug, d.Get("promote_from").(string)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
 
+		tflog.Trace(ctx, "updating resource")
+		_, err := api.UpdateResource(serviceSlug, resourceSlug, clarity.UpdateResourceRequest{
+			Name:          name,
+			Configuration: expandConfiguration(d),
+			PromoteFrom:   d.Get("promote_from").(string),
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("deployment") {
 		internal, err := api.ReadResource(serviceSlug, resourceSlug)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		if internal.ManualUserInterfaceTrigger() != userInterfaceTrigger {
-			tflog.Trace(ctx, fmt.Sprintf("updating user interface trigger: %v", userInterfaceTrigger))
-
-			var strategy clarity.UpdateDeploymentStrategy
-			if userInterfaceTrigger {
-				strategy = internal.EnableUserInterfaceTrigger()
-			} else {
-				strategy = internal.DisableUserInterfaceTrigger()
-			}
 
-			if err := api.UpdateResourceDeploymentStrategy(serviceSlug, resourceSlug, strategy); err != nil {
-				return diag.FromErr(err)
-			}
+		tflog.Trace(ctx, "updating deployment strategy")
+		strategy := clarity.UpdateDeploymentStrategy{
+			Strategy: expandDeployment(d.Get("deployment").([]interface{}), internal.Deployment),
+		}
+		if err := api.UpdateResourceDeploymentStrategy(serviceSlug, resourceSlug, strategy); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -225,7 +552,10 @@ func resourceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{
 
 func resourceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	api := meta.(*clarity.Client)
-	serviceSlug, resourceSlug := parseID(d.Id())
+	serviceSlug, resourceSlug, diags := parseID(d.Id())
+	if diags.HasError() {
+		return diags
+	}
 
 	err := api.DeleteResource(serviceSlug, resourceSlug)
 	if err != nil {
@@ -235,43 +565,139 @@ func resourceDelete(ctx context.Context, d *schema.ResourceData, meta interface{
 	return nil
 }
 
-func lambdaHash(in interface{}) int {
-	var buf bytes.Buffer
-	m := in.(map[string]interface{})
-	for k, v := range m {
-		buf.WriteString(fmt.Sprintf("%s#%s", k, v.(string)))
+// expandConfiguration returns the configuration of whichever resource type
+// block is set, validation enforces exactly one is.
+func expandConfiguration(d *schema.ResourceData) clarity.Configuration {
+	if v, ok := d.GetOk("ecs"); ok && len(v.([]interface{})) > 0 {
+		return expandECS(v.([]interface{})[0].(map[string]interface{}))
+	}
+	if v, ok := d.GetOk("kubernetes_deployment"); ok && len(v.([]interface{})) > 0 {
+		return expandKubernetes(v.([]interface{})[0].(map[string]interface{}))
 	}
+	if v, ok := d.GetOk("cloud_run"); ok && len(v.([]interface{})) > 0 {
+		return expandCloudRun(v.([]interface{})[0].(map[string]interface{}))
+	}
+
+	lambda := d.Get("lambda").([]interface{})[0]
+	return expandLambda(lambda.(map[string]interface{}))
+}
 
-	return HashString(buf.String())
+// resourceRequestType selects whether Clarity creates the underlying resource
+// or imports an existing one.
+func resourceRequestType(d *schema.ResourceData) string {
+	for _, l := range d.Get("lambda").([]interface{}) {
+		if l.(map[string]interface{})["create_alias"].(bool) {
+			return clarity.RequestTypeCreate
+		}
+	}
+	return clarity.RequestTypeImport
 }
 
-func deploymentHash(in interface{}) int {
-	var buf bytes.Buffer
-	m := in.(map[string]interface{})
+func expandLambda(lambda map[string]interface{}) clarity.Configuration {
+	// version is only part of the clarity_resource lambda block
+	version, _ := lambda["version"].(string)
 
-	triggerInterface := m["trigger"]
-	triggerSchema := triggerInterface.(*schema.Set).List()[0]
-	trigger := triggerSchema.(map[string]interface{})
-	if userInterface, ok := trigger["manual_user_interface"].(bool); ok {
-		buf.WriteString(fmt.Sprintf("manual_user_interface#%v", userInterface))
+	return clarity.Configuration{
+		TypeSwitch: clarity.LambdaResourceType,
+		LambdaConfiguration: &clarity.LambdaConfiguration{
+			Name:    lambda["function_name"].(string),
+			Alias:   lambda["alias"].(string),
+			Version: version,
+		},
+	}
+}
+
+func expandECS(ecs map[string]interface{}) clarity.Configuration {
+	return clarity.Configuration{
+		TypeSwitch: clarity.ECSResourceType,
+		ECSConfiguration: &clarity.ECSConfiguration{
+			Cluster:   ecs["cluster"].(string),
+			Service:   ecs["service_name"].(string),
+			Container: ecs["container_name"].(string),
+		},
 	}
+}
 
-	return HashString(buf.String())
+func flattenECS(conf *clarity.ECSConfiguration) map[string]interface{} {
+	return map[string]interface{}{
+		"cluster":        conf.Cluster,
+		"service_name":   conf.Service,
+		"container_name": conf.Container,
+	}
 }
 
-func triggerHash(in interface{}) int {
-	var buf bytes.Buffer
-	m := in.(map[string]interface{})
-	if userInterface, ok := m["manual_user_interface"]; ok {
-		buf.WriteString(fmt.Sprintf("manual_user_interface#%v", userInterface))
+func expandKubernetes(k8s map[string]interface{}) clarity.Configuration {
+	return clarity.Configuration{
+		TypeSwitch: clarity.KubernetesResourceType,
+		KubernetesConfiguration: &clarity.KubernetesConfiguration{
+			Namespace:  k8s["namespace"].(string),
+			Deployment: k8s["deployment_name"].(string),
+			Container:  k8s["container"].(string),
+		},
 	}
+}
 
-	return HashString(buf.String())
+func flattenKubernetes(conf *clarity.KubernetesConfiguration) map[string]interface{} {
+	return map[string]interface{}{
+		"namespace":       conf.Namespace,
+		"deployment_name": conf.Deployment,
+		"container":       conf.Container,
+	}
+}
+
+func expandCloudRun(cloudRun map[string]interface{}) clarity.Configuration {
+	return clarity.Configuration{
+		TypeSwitch: clarity.CloudRunResourceType,
+		CloudRunConfiguration: &clarity.CloudRunConfiguration{
+			Project:    cloudRun["project"].(string),
+			Region:     cloudRun["region"].(string),
+			Service:    cloudRun["service_name"].(string),
+			TrafficTag: cloudRun["traffic_tag"].(string),
+		},
+	}
+}
+
+func flattenCloudRun(conf *clarity.CloudRunConfiguration) map[string]interface{} {
+	return map[string]interface{}{
+		"project":      conf.Project,
+		"region":       conf.Region,
+		"service_name": conf.Service,
+		"traffic_tag":  conf.TrafficTag,
+	}
+}
+
+func flattenLambda(conf *clarity.LambdaConfiguration) map[string]interface{} {
+	return map[string]interface{}{
+		"function_name": conf.Name,
+		"alias":         conf.Alias,
+	}
+}
+
+// suppressAfterCreate ignores changes to values only sent when creating, the
+// server does not report them back so they are unknown after an import.
+func suppressAfterCreate(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	return d.Id() != ""
+}
+
+// withLambdaCreateOnly copies the create-only values from the prior lambda
+// block, these are not reported back by the server.
+func withLambdaCreateOnly(lambda map[string]interface{}, prior []interface{}) map[string]interface{} {
+	lambda["create_alias"] = false
+	lambda["version"] = ""
+	if len(prior) == 1 {
+		p := prior[0].(map[string]interface{})
+		lambda["create_alias"] = p["create_alias"]
+		lambda["version"] = p["version"]
+	}
+	return lambda
 }
 
 func resourceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	api := meta.(*clarity.Client)
-	serviceSlug, resourceSlug := parseID(d.Id())
+	serviceSlug, resourceSlug, diags := parseID(d.Id())
+	if diags.HasError() {
+		return diags
+	}
 
 	internal, err := api.ReadResource(serviceSlug, resourceSlug)
 	if err != nil {
@@ -287,32 +713,37 @@ func resourceRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 	d.Set("service_slug", serviceSlug)
 	d.Set("name", internal.Name)
 
-	// TODO Support more resource types
-	switch internal.Data.Type {
-	case "lambda":
-		lambdaConf := internal.Data.LambdaConfiguration
-		d.Set("lambda", schema.NewSet(lambdaHash, []interface{}{
-			map[string]interface{}{
-				"function_name": lambdaConf.Name,
-				"alias":         lambdaConf.Alias,
-			},
-		}))
-	}
-
-	// TODO Support full deployment configuration
-	if len(internal.Deployment.Trigger) == 1 {
-		d.Set("deployment", schema.NewSet(deploymentHash, []interface{}{
-			map[string]interface{}{
-				"trigger": schema.NewSet(triggerHash, []interface{}{
-					map[string]interface{}{
-						"manual_user_interface": internal.ManualUserInterfaceTrigger(),
-					},
-				}),
-			},
-		}))
+	switch {
+	case internal.Data.LambdaConfiguration != nil:
+		lambda := withLambdaCreateOnly(flattenLambda(internal.Data.LambdaConfiguration), d.Get("lambda").([]interface{}))
+		d.Set("lambda", []interface{}{lambda})
+	case internal.Data.ECSConfiguration != nil:
+		d.Set("ecs", []interface{}{
+			flattenECS(internal.Data.ECSConfiguration),
+		})
+	case internal.Data.KubernetesConfiguration != nil:
+		d.Set("kubernetes_deployment", []interface{}{
+			flattenKubernetes(internal.Data.KubernetesConfiguration),
+		})
+	case internal.Data.CloudRunConfiguration != nil:
+		d.Set("cloud_run", []interface{}{
+			flattenCloudRun(internal.Data.CloudRunConfiguration),
+		})
 	}
 
+	d.Set("deployment", flattenDeployment(internal.Deployment, d.Get("deployment.0.trigger").([]interface{})))
+	d.Set("promote_from", internal.PromoteFrom)
 	d.Set("slug", internal.Slug)
 
-	return nil
+	return unknownResourceDiagnostics(internal)
+}
+
+// unknownResourceDiagnostics warns when a resource has a type this version of
+// the terraform provider does not recognise, rather than failing outright.
+func unknownResourceDiagnostics(r *clarity.InternalResource) diag.Diagnostics {
+	if !r.Data.IsUnknown() {
+		return nil
+	}
+
+	return diag.Diagnostics{unknownTypeWarning("resource", r.Data.Type, r.Slug)}
 }
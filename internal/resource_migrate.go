@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceResourceV0 is the schema of clarity_resource before IDs were
+// rendered with the '/' separator, it is only used to decode prior state.
+func resourceResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"provider_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"service_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"lambda": {
+				Type:     schema.TypeSet,
+				MaxItems: 1,
+				MinItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"function_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"alias": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"deployment": {
+				Type:     schema.TypeSet,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"trigger": {
+							Type:     schema.TypeSet,
+							MaxItems: 1,
+							Required: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"manual_user_interface": {
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"slug": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Rewrites '<service>#<resource>' IDs with the canonical separator.
+func resourceStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	id, _ := rawState["id"].(string)
+	service, resource, err := splitID(id)
+	if err != nil {
+		return nil, fmt.Errorf("upgrading state: %w", err)
+	}
+
+	rawState["id"] = renderID(service, resource)
+
+	return rawState, nil
+}
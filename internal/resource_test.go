@@ -1,14 +1,20 @@
 package internal
 
 import (
+	"context"
+	"fmt"
+	"reflect"
 	"regexp"
 	"testing"
 
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccResource(t *testing.T) {
 	config := testAccProvider() + testAccService + testAccResource
+	var id string
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: providerFactories,
@@ -25,18 +31,75 @@ func TestAccResource(t *testing.T) {
 					resource.TestCheckResourceAttr("clarity_resource.test", "lambda.0.alias", "clarity"),
 					resource.TestMatchResourceAttr(
 						"clarity_resource.test", "slug", regexp.MustCompile("^terraform-test")),
+					testAccStoreResourceID("clarity_resource.test", &id),
 				),
 			},
 			{
 				ResourceName:            "clarity_resource.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"force_destroy"},
+				ImportStateVerifyIgnore: []string{"force_destroy", "idempotency_key"},
+			},
+			{
+				ResourceName:            "clarity_resource.test",
+				ImportState:             true,
+				ImportStateId:           "terraform-test/terraform-test",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_destroy", "idempotency_key"},
+			},
+			{
+				Config: testAccProvider() + testAccService + testAccResourceRenamed,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_resource.test", "name", "terraform-test-renamed"),
+					resource.TestCheckResourceAttr("clarity_resource.test", "lambda.0.function_name", "terraform-test"),
+					resource.TestMatchResourceAttr(
+						"clarity_resource.test", "slug", regexp.MustCompile("^terraform-test")),
+					testAccCheckResourceID("clarity_resource.test", &id, true),
+				),
+			},
+			{
+				Config: testAccProvider() + testAccService + testAccResourceAliasChanged,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_resource.test", "lambda.0.alias", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_resource.test", "lambda.0.function_name", "terraform-test"),
+					testAccCheckResourceID("clarity_resource.test", &id, true),
+				),
 			},
 		},
 	})
 }
 
+// testAccStoreResourceID records the ID of the named resource for a later
+// step to compare with.
+func testAccStoreResourceID(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource '%s' not found in state", name)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+// testAccCheckResourceID checks whether the named resource kept the ID stored
+// by an earlier step, that is whether it was updated rather than replaced.
+func testAccCheckResourceID(name string, id *string, same bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource '%s' not found in state", name)
+		}
+		if same && rs.Primary.ID != *id {
+			return fmt.Errorf("expected '%s' to be updated in place, ID changed from '%s' to '%s'", name, *id, rs.Primary.ID)
+		}
+		if !same && rs.Primary.ID == *id {
+			return fmt.Errorf("expected '%s' to be replaced, ID is still '%s'", name, *id)
+		}
+		return nil
+	}
+}
+
 const testAccResource = `
 resource "clarity_resource" "test" {
   provider_slug = clarity_provider.test.slug
@@ -48,3 +111,428 @@ resource "clarity_resource" "test" {
   }
 }
 `
+
+const testAccResourceRenamed = `
+resource "clarity_resource" "test" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.test.slug
+  name = "terraform-test-renamed"
+
+  lambda {
+    function_name = "terraform-test"
+  }
+}
+`
+
+const testAccResourceAliasChanged = `
+resource "clarity_resource" "test" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.test.slug
+  name = "terraform-test-renamed"
+
+  lambda {
+    function_name = "terraform-test"
+    alias = "terraform-test"
+  }
+}
+`
+
+func TestAccResourceECS(t *testing.T) {
+	config := testAccProvider() + testAccContainerService + testAccResourceECS
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_resource.ecs", "name", "terraform-test-ecs"),
+					resource.TestCheckResourceAttr("clarity_resource.ecs", "ecs.0.cluster", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_resource.ecs", "ecs.0.service_name", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_resource.ecs", "ecs.0.container_name", "app"),
+					resource.TestCheckResourceAttr("clarity_resource.ecs", "lambda.#", "0"),
+				),
+			},
+			{
+				ResourceName:            "clarity_resource.ecs",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"idempotency_key"},
+			},
+		},
+	})
+}
+
+const testAccContainerService = `
+resource "clarity_service" "container" {
+  provider_slug = clarity_provider.test.slug
+  name = "terraform-test-container"
+  type = "container"
+}
+`
+
+const testAccResourceECS = `
+resource "clarity_resource" "ecs" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.container.slug
+  name = "terraform-test-ecs"
+
+  ecs {
+    cluster = "terraform-test"
+    service_name = "terraform-test"
+    container_name = "app"
+  }
+}
+`
+
+func TestAccResourceKubernetes(t *testing.T) {
+	config := testAccProvider() + testAccContainerService + testAccResourceKubernetes
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_resource.kubernetes", "name", "terraform-test-kubernetes"),
+					resource.TestCheckResourceAttr("clarity_resource.kubernetes", "kubernetes_deployment.0.namespace", "default"),
+					resource.TestCheckResourceAttr("clarity_resource.kubernetes", "kubernetes_deployment.0.deployment_name", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_resource.kubernetes", "kubernetes_deployment.0.container", "app"),
+				),
+			},
+			{
+				ResourceName:            "clarity_resource.kubernetes",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"idempotency_key"},
+			},
+		},
+	})
+}
+
+const testAccResourceKubernetes = `
+resource "clarity_resource" "kubernetes" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.container.slug
+  name = "terraform-test-kubernetes"
+
+  kubernetes_deployment {
+    deployment_name = "terraform-test"
+    container = "app"
+  }
+}
+`
+
+// The provider and service are created in a first step and looked up by name
+// in the second, so their slugs are known when planning the resource and the
+// incompatibility is reported by the plan itself.
+func TestAccResourceCloudRunIncompatibleProvider(t *testing.T) {
+	config := testAccProvider() + testAccContainerService
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config:      config + testAccResourceCloudRun,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`of type 'aws' is unable to deploy 'cloud-run' resources`),
+			},
+		},
+	})
+}
+
+const testAccResourceCloudRun = `
+data "clarity_provider" "existing" {
+  name = "terraform-test"
+}
+
+data "clarity_service" "existing" {
+  name = "terraform-test-container"
+}
+
+resource "clarity_resource" "cloud_run" {
+  provider_slug = data.clarity_provider.existing.slug
+  service_slug = data.clarity_service.existing.slug
+  name = "terraform-test-cloud-run"
+
+  cloud_run {
+    project = "terraform-test"
+    region = "europe-west1"
+    service_name = "terraform-test"
+  }
+}
+`
+
+func TestAccResourceCreateAlias(t *testing.T) {
+	config := testAccProvider() + testAccService + testAccResourceCreateAlias
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_resource.created", "lambda.0.function_name", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_resource.created", "lambda.0.alias", "terraform-test-created"),
+					resource.TestCheckResourceAttr("clarity_resource.created", "lambda.0.create_alias", "true"),
+					testAccStoreResourceID("clarity_resource.created", &id),
+				),
+			},
+			{
+				ResourceName:            "clarity_resource.created",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"lambda.0.create_alias", "lambda.0.version", "idempotency_key"},
+			},
+			{
+				Config: testAccProvider() + testAccService + testAccResourceCreateAliasChanged,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_resource.created", "lambda.0.alias", "terraform-test-recreated"),
+					testAccCheckResourceID("clarity_resource.created", &id, false),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceCreateAlias = `
+resource "clarity_resource" "created" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.test.slug
+  name = "terraform-test-created"
+
+  lambda {
+    function_name = "terraform-test"
+    alias = "terraform-test-created"
+    create_alias = true
+  }
+}
+`
+
+const testAccResourceCreateAliasChanged = `
+resource "clarity_resource" "created" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.test.slug
+  name = "terraform-test-created"
+
+  lambda {
+    function_name = "terraform-test"
+    alias = "terraform-test-recreated"
+    create_alias = true
+  }
+}
+`
+
+func TestAccResourcePromoteFrom(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProvider() + testAccService + testAccResourcePromoteFrom("clarity_resource.staging.slug"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("clarity_resource.prod", "promote_from", "clarity_resource.staging", "slug"),
+					testAccStoreResourceID("clarity_resource.prod", &id),
+				),
+			},
+			{
+				Config: testAccProvider() + testAccService + testAccResourcePromoteFrom("clarity_resource.dev.slug"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("clarity_resource.prod", "promote_from", "clarity_resource.dev", "slug"),
+					testAccCheckResourceID("clarity_resource.prod", &id, true),
+				),
+			},
+			{
+				Config: testAccProvider() + testAccService + testAccResourcePromoteFrom("null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_resource.prod", "promote_from", ""),
+					testAccCheckResourceID("clarity_resource.prod", &id, true),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourcePromoteFrom(promoteFrom string) string {
+	return fmt.Sprintf(`
+resource "clarity_resource" "dev" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.test.slug
+  name = "terraform-test-dev"
+
+  lambda {
+    function_name = "terraform-test"
+    alias = "dev"
+  }
+}
+
+resource "clarity_resource" "staging" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.test.slug
+  name = "terraform-test-staging"
+  promote_from = clarity_resource.dev.slug
+
+  lambda {
+    function_name = "terraform-test"
+    alias = "staging"
+  }
+}
+
+resource "clarity_resource" "prod" {
+  provider_slug = clarity_provider.test.slug
+  service_slug = clarity_service.test.slug
+  name = "terraform-test-prod"
+  promote_from = %s
+
+  lambda {
+    function_name = "terraform-test"
+    alias = "prod"
+  }
+}
+`, promoteFrom)
+}
+
+// create_alias and version are not reported by the server, once imported they
+// must not plan a replacement of the resource.
+func TestResourceLambdaCreateOnlyAfterImport(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "service/resource",
+		Attributes: map[string]string{
+			"id":                     "service/resource",
+			"provider_slug":          "provider",
+			"service_slug":           "service",
+			"name":                   "example",
+			"lambda.#":               "1",
+			"lambda.0.function_name": "function",
+			"lambda.0.alias":         "live",
+			"lambda.0.create_alias":  "false",
+			"lambda.0.version":       "",
+			"deployment.#":           "0",
+			"adopt_existing":         "false",
+			"slug":                   "resource",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"provider_slug": "provider",
+		"service_slug":  "service",
+		"name":          "example",
+		"lambda": []interface{}{
+			map[string]interface{}{"function_name": "function", "alias": "live", "create_alias": true, "version": "3"},
+		},
+	})
+
+	// Only the schema's own diff is under test, the plan time checks call the API.
+	r := resourceResource()
+	r.CustomizeDiff = nil
+
+	diff, err := r.SimpleDiff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("error planning: %s", err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Errorf("expected no changes, got %v", diff.Attributes)
+	}
+}
+
+func TestValidateLambda(t *testing.T) {
+	lambda := func(createAlias bool, version string) []interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				"function_name": "function",
+				"alias":         "clarity",
+				"create_alias":  createAlias,
+				"version":       version,
+			},
+		}
+	}
+
+	if err := validateLambda(lambda(true, "3")); err != nil {
+		t.Errorf("expected version with create_alias to be valid: %v", err)
+	}
+	if err := validateLambda(lambda(false, "")); err != nil {
+		t.Errorf("expected import to be valid: %v", err)
+	}
+	if err := validateLambda(lambda(false, "3")); err == nil {
+		t.Errorf("expected version without create_alias to be invalid")
+	}
+}
+
+func TestSplitID(t *testing.T) {
+	cases := map[string][2]string{
+		"service/resource":    {"service", "resource"},
+		"service#resource":    {"service", "resource"},
+		"My API/v1/v2":        {"My API", "v1/v2"},
+		"My API/#1":           {"My API", "#1"},
+		"My%2FAPI/resource#1": {"My%2FAPI", "resource#1"},
+		"service#a#b":         {"service", "a#b"},
+	}
+	for input, expected := range cases {
+		service, resource, err := splitID(input)
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %v", input, err)
+		}
+		if service != expected[0] || resource != expected[1] {
+			t.Errorf("splitID(%q) = %q, %q, expected %q, %q", input, service, resource, expected[0], expected[1])
+		}
+	}
+
+	for _, input := range []string{"", "service", "service/", "/resource", "#resource", "service#"} {
+		if _, _, err := splitID(input); err == nil {
+			t.Errorf("expected an error for '%s'", input)
+		}
+	}
+}
+
+func TestResourceStateUpgradeV0(t *testing.T) {
+	expected := map[string]interface{}{
+		"id":   "service/resource",
+		"slug": "resource",
+	}
+
+	actual, err := resourceStateUpgradeV0(context.Background(), map[string]interface{}{
+		"id":   "service#resource",
+		"slug": "resource",
+	}, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+// State written while lambda was a set is an array either way, it must keep
+// decoding now the block is a list.
+func TestResourceLambdaSetStateCompatibility(t *testing.T) {
+	r := resourceResource()
+
+	val, err := ctyjson.Unmarshal([]byte(`{
+		"id": "service/resource",
+		"name": "example",
+		"provider_slug": "provider",
+		"service_slug": "service",
+		"lambda": [{"function_name": "fn", "alias": "live", "create_alias": false, "version": ""}]
+	}`), r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding state: %s", err)
+	}
+
+	state, err := r.ShimInstanceStateFromValue(val)
+	if err != nil {
+		t.Fatalf("error shimming state: %s", err)
+	}
+
+	d := r.Data(state)
+	if v := d.Get("lambda.0.function_name"); v != "fn" {
+		t.Errorf("expected function_name 'fn', got %q", v)
+	}
+	if v := d.Get("lambda.0.alias"); v != "live" {
+		t.Errorf("expected alias 'live', got %q", v)
+	}
+}
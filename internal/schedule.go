@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	// Deployment windows are validated against IANA time zones, embed the
+	// database so validation doesn't depend on the host.
+	_ "time/tzdata"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var clockRegexp = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+
+var weekdays = map[string]int{
+	"SUN": 0,
+	"MON": 1,
+	"TUE": 2,
+	"WED": 3,
+	"THU": 4,
+	"FRI": 5,
+	"SAT": 6,
+}
+
+func scheduleSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Only deploy within the given windows, deployments outside a window are queued until the next one opens.",
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"time_zone": {
+					Description:  "IANA time zone the windows are evaluated in, e.g. `Europe/London`.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "UTC",
+					ValidateFunc: validateTimeZone,
+				},
+				"window": {
+					Description: "A recurring deployment window.",
+					Type:        schema.TypeList,
+					MinItems:    1,
+					Required:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"days": {
+								Description:  "Days of the week in cron syntax, e.g. `TUE-THU`, `MON,WED,FRI` or `*`.",
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      "*",
+								ValidateFunc: validateWeekdays,
+							},
+							"start": {
+								Description:  "Time the window opens, `HH:MM`.",
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validateClock,
+							},
+							"end": {
+								Description:  "Time the window closes, `HH:MM`.",
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validateClock,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateTimeZone(v interface{}, k string) (ws []string, errs []error) {
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if _, err := time.LoadLocation(value); err != nil || value == "" || value == "Local" {
+		errs = append(errs, fmt.Errorf("%s: unknown time zone '%s', expected an IANA time zone such as 'Europe/London'", k, value))
+	}
+	return
+}
+
+func validateClock(v interface{}, k string) (ws []string, errs []error) {
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if !clockRegexp.MatchString(value) {
+		errs = append(errs, fmt.Errorf("%s: invalid time '%s', expected 'HH:MM'", k, value))
+	}
+	return
+}
+
+func validateWeekdays(v interface{}, k string) (ws []string, errs []error) {
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if _, err := parseWeekdays(value); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %v", k, err))
+	}
+	return
+}
+
+// parseWeekdays parses a cron day of week field, supporting `*`, lists and
+// ranges of day names or numbers (0 or 7 for Sunday).
+func parseWeekdays(value string) ([]int, error) {
+	if value == "*" {
+		return []int{0, 1, 2, 3, 4, 5, 6}, nil
+	}
+
+	parseDay := func(day string) (int, error) {
+		if n, ok := weekdays[strings.ToUpper(day)]; ok {
+			return n, nil
+		}
+		if n, err := strconv.Atoi(day); err == nil && n >= 0 && n <= 7 {
+			return n, nil
+		}
+		return 0, fmt.Errorf("invalid day '%s', expected one of SUN, MON, TUE, WED, THU, FRI, SAT or 0-7", day)
+	}
+
+	seen := make(map[int]bool)
+	var days []int
+	for _, part := range strings.Split(value, ",") {
+		bounds := strings.Split(part, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid range '%s'", part)
+		}
+
+		from, err := parseDay(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, err = parseDay(bounds[1]); err != nil {
+				return nil, err
+			}
+			if to < from {
+				return nil, fmt.Errorf("invalid range '%s', ranges must not wrap past the end of the week", part)
+			}
+		}
+
+		for d := from; d <= to; d++ {
+			if !seen[d%7] {
+				seen[d%7] = true
+				days = append(days, d%7)
+			}
+		}
+	}
+
+	return days, nil
+}
+
+func expandSchedule(in []interface{}) *clarity.DeploymentSchedule {
+	if len(in) == 0 || in[0] == nil {
+		return nil
+	}
+	m := in[0].(map[string]interface{})
+
+	schedule := &clarity.DeploymentSchedule{
+		TimeZone: m["time_zone"].(string),
+		Windows:  make([]clarity.DeploymentWindow, 0),
+	}
+	for _, w := range m["window"].([]interface{}) {
+		if w == nil {
+			continue
+		}
+		window := w.(map[string]interface{})
+		schedule.Windows = append(schedule.Windows, clarity.DeploymentWindow{
+			Days:  window["days"].(string),
+			Start: window["start"].(string),
+			End:   window["end"].(string),
+		})
+	}
+	return schedule
+}
+
+func flattenSchedule(schedule *clarity.DeploymentSchedule) []interface{} {
+	if schedule == nil {
+		return []interface{}{}
+	}
+
+	windows := make([]interface{}, 0, len(schedule.Windows))
+	for _, w := range schedule.Windows {
+		windows = append(windows, map[string]interface{}{
+			"days":  w.Days,
+			"start": w.Start,
+			"end":   w.End,
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"time_zone": schedule.TimeZone,
+			"window":    windows,
+		},
+	}
+}
+
+// validateSchedule requires every window to close after it opens.
+func validateSchedule(schedule *clarity.DeploymentSchedule) error {
+	if schedule == nil {
+		return nil
+	}
+
+	for i, w := range schedule.Windows {
+		// HH:MM compares lexically
+		if w.Start >= w.End {
+			return fmt.Errorf("deployment schedule window %d: start '%s' must be before end '%s'", i+1, w.Start, w.End)
+		}
+	}
+	return nil
+}
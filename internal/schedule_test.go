@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+)
+
+func TestParseWeekdays(t *testing.T) {
+	cases := map[string][]int{
+		"*":           {0, 1, 2, 3, 4, 5, 6},
+		"TUE-THU":     {2, 3, 4},
+		"mon,wed,fri": {1, 3, 5},
+		"1-7":         {1, 2, 3, 4, 5, 6, 0},
+		"SUN,6":       {0, 6},
+	}
+	for input, expected := range cases {
+		actual, err := parseWeekdays(input)
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %v", input, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("parseWeekdays(%q) = %v, expected %v", input, actual, expected)
+		}
+	}
+
+	for _, input := range []string{"", "FUN", "THU-TUE", "8", "MON-TUE-WED", "MON,"} {
+		if _, err := parseWeekdays(input); err == nil {
+			t.Errorf("expected an error for '%s'", input)
+		}
+	}
+}
+
+func TestValidateTimeZone(t *testing.T) {
+	if _, errs := validateTimeZone("Europe/London", "time_zone"); len(errs) > 0 {
+		t.Errorf("expected Europe/London to be valid: %v", errs)
+	}
+	for _, tz := range []string{"", "Local", "Europe/Nowhere"} {
+		if _, errs := validateTimeZone(tz, "time_zone"); len(errs) == 0 {
+			t.Errorf("expected '%s' to be invalid", tz)
+		}
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	valid := &clarity.DeploymentSchedule{
+		TimeZone: "Europe/London",
+		Windows:  []clarity.DeploymentWindow{{Days: "TUE-THU", Start: "09:00", End: "16:00"}},
+	}
+	if err := validateSchedule(valid); err != nil {
+		t.Errorf("expected schedule to be valid: %v", err)
+	}
+
+	invalid := &clarity.DeploymentSchedule{
+		TimeZone: "Europe/London",
+		Windows:  []clarity.DeploymentWindow{{Days: "*", Start: "16:00", End: "09:00"}},
+	}
+	if err := validateSchedule(invalid); err == nil {
+		t.Errorf("expected schedule to be invalid")
+	}
+}
@@ -2,6 +2,8 @@ package internal
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -17,41 +19,182 @@ func serviceResource() *schema.Resource {
 
 		CreateContext: serviceCreate,
 		ReadContext:   serviceRead,
+		UpdateContext: serviceUpdate,
 		DeleteContext: serviceDelete,
+		CustomizeDiff: serviceCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: serviceImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"provider_slug": {
 				Description:  "Provider slug.",
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Required:     true,
 				ValidateFunc: validation.StringLenBetween(0, 128),
 			},
 			"name": {
 				Description:  "A name for the service.",
 				Type:         schema.TypeString,
-				ForceNew:     true,
 				Required:     true,
 				ValidateFunc: validation.StringLenBetween(0, 128),
 			},
+			"type": {
+				Description:  "The type of service, one of `function`, `container` or `static-site`. It may change in place as long as the resources attached to the service are supported by the new type.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      clarity.ServiceTypeFunction,
+				ValidateFunc: validation.StringInSlice(clarity.ServiceTypes, false),
+			},
+			"adopt_existing":  adoptExistingSchema(),
+			"idempotency_key": idempotencyKeySchema(),
 			"slug": {
 				Description: "A slug for this service.",
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
 			},
+			"description": {
+				Description:  "A description of the service.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, 1024),
+			},
+			"owner_team": {
+				Description:  "The team owning the service.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, 128),
+			},
+			"repository_url": {
+				Description:  "URL of the source repository for the service.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+			"links": {
+				Description: "Named links for the service, such as a runbook or dashboard.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description:  "A name for the link.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 128),
+						},
+						"url": {
+							Description:  "The link URL.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+						},
+					},
+				},
+			},
+			"resource":  inlineResourceSchema(),
+			"resources": serviceResourcesSchema(),
+			"force_destroy": {
+				Description: "Delete all resources attached to this service when destroying it.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
 		},
 	}
 }
 
+// serviceResourcesSchema lists every resource attached to a service, including
+// those managed outside of this configuration.
+func serviceResourcesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "All resources attached to the service.",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Description: "The name of the resource.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"slug": {
+					Description: "The slug of the resource.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"provider_slug": {
+					Description: "The slug of the provider the resource is deployed with.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func flattenServiceResources(resources []clarity.Resource) []interface{} {
+	out := make([]interface{}, 0, len(resources))
+	for _, r := range resources {
+		out = append(out, map[string]interface{}{
+			"name":          r.Name,
+			"slug":          r.Slug,
+			"provider_slug": r.Provider,
+		})
+	}
+	return out
+}
+
+func expandServiceMetadata(d *schema.ResourceData) clarity.ServiceMetadata {
+	links := make([]clarity.ServiceLink, 0)
+	for _, l := range d.Get("links").([]interface{}) {
+		m := l.(map[string]interface{})
+		links = append(links, clarity.ServiceLink{
+			Name: m["name"].(string),
+			URL:  m["url"].(string),
+		})
+	}
+
+	return clarity.ServiceMetadata{
+		Description:   d.Get("description").(string),
+		OwnerTeam:     d.Get("owner_team").(string),
+		RepositoryURL: d.Get("repository_url").(string),
+		Links:         links,
+	}
+}
+
+func flattenServiceLinks(links []clarity.ServiceLink) []interface{} {
+	out := make([]interface{}, 0, len(links))
+	for _, l := range links {
+		out = append(out, map[string]interface{}{
+			"name": l.Name,
+			"url":  l.URL,
+		})
+	}
+	return out
+}
+
 func serviceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*clarity.Client)
 
 	providerSlug := d.Get("provider_slug").(string)
 	name := d.Get("name").(string)
+	serviceType := d.Get("type").(string)
+	inlineResources := d.Get("resource").([]interface{})
+
+	req := clarity.ServiceCreateRequest{
+		Name:               name,
+		Resources:          expandInlineResources(inlineResources),
+		RepositoryProvider: providerSlug,
+		ServiceType:        serviceType,
+		ServiceMetadata:    expandServiceMetadata(d),
+	}
 
 	resp, err := client.ListServices()
 	if err != nil {
@@ -59,28 +202,78 @@ func serviceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}
 	}
 
 	for _, s := range resp.Services {
-		if s.Name == name {
+		if s.Name != name {
+			continue
+		}
+
+		if !d.Get("adopt_existing").(bool) {
 			return diag.Errorf("Conflict. A service with the name '%s' already exissts.", s.Name)
 		}
+
+		same, err := sameService(client, s, req)
+		if err != nil {
+			return diag.Errorf("loading service '%s' to confirm uniqueness: %v", s.Slug, err)
+		}
+		if same {
+			d.SetId(s.Slug)
+			d.Set("resource", withInlineSlugs(inlineResources, s.Resources))
+			diags := diag.Diagnostics{adoptedWarning("service", name, s.Slug)}
+			return append(diags, serviceRead(ctx, d, meta)...)
+		}
+
+		return diag.Errorf("Conflict. A service with the name '%s' already exissts.", s.Name)
 	}
 
-	service, err := client.CreateService(clarity.ServiceCreateRequest{
-		Name:               name,
-		Resources:          make([]clarity.CreateResourceRequest, 0),
-		RepositoryProvider: providerSlug,
-		ServiceType:        "function",
+	key := idempotencyKey(d)
+	var service *clarity.Service
+	err = createWithRetry(ctx, d.Timeout(schema.TimeoutCreate), func() (err error) {
+		service, err = client.CreateService(req, key)
+		return err
 	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	d.SetId(service.Slug)
+	d.Set("resource", withInlineSlugs(inlineResources, service.Resources))
 
 	tflog.Trace(ctx, "created a service")
 
 	return serviceRead(ctx, d, meta)
 }
 
+// sameService reports whether an existing service matches every field of the
+// create request, including its inline resources.
+func sameService(api *clarity.Client, existing clarity.Service, req clarity.ServiceCreateRequest) (bool, error) {
+	if existing.Provider == nil ||
+		existing.Provider.Slug != req.RepositoryProvider ||
+		existing.ServiceType != req.ServiceType ||
+		!sameServiceMetadata(existing.ServiceMetadata, req.ServiceMetadata) ||
+		len(existing.Resources) != len(req.Resources) {
+		return false, nil
+	}
+
+	slugs := make(map[string]string, len(existing.Resources))
+	for _, r := range existing.Resources {
+		slugs[r.Name] = r.Slug
+	}
+	for _, r := range req.Resources {
+		slug, ok := slugs[r.Name]
+		if !ok {
+			return false, nil
+		}
+
+		internal, err := api.ReadResource(existing.Slug, slug)
+		if err != nil {
+			return false, err
+		}
+		if !sameResource(internal, r) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func serviceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*clarity.Client)
 	slug := d.Id()
@@ -93,7 +286,19 @@ func serviceRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 	if service != nil {
 		d.Set("provider_slug", service.Provider.Slug)
 		d.Set("name", service.Name)
+		d.Set("type", service.ServiceType)
 		d.Set("slug", service.Slug)
+		d.Set("resources", flattenServiceResources(service.Resources))
+		d.Set("description", service.Description)
+		d.Set("owner_team", service.OwnerTeam)
+		d.Set("repository_url", service.RepositoryURL)
+		d.Set("links", flattenServiceLinks(service.Links))
+
+		inlineResources, err := readInlineResources(client, slug, d.Get("resource").([]interface{}))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("resource", inlineResources)
 	} else {
 		d.SetId("")
 	}
@@ -101,9 +306,124 @@ func serviceRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+func serviceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clarity.Client)
+	slug := d.Id()
+
+	if d.HasChanges("name", "provider_slug", "type", "description", "owner_team", "repository_url", "links") {
+		name := d.Get("name").(string)
+
+		if d.HasChange("name") {
+			resp, err := client.ListServices()
+			if err != nil {
+				return diag.Errorf("loading services to confirm uniqueness: %v", err)
+			}
+
+			for _, s := range resp.Services {
+				if s.Name == name && s.Slug != slug {
+					return diag.Errorf("Conflict. A service with the name '%s' already exists.", s.Name)
+				}
+			}
+		}
+
+		_, err := client.UpdateService(slug, clarity.ServiceUpdateRequest{
+			Name:               name,
+			RepositoryProvider: d.Get("provider_slug").(string),
+			ServiceType:        d.Get("type").(string),
+			ServiceMetadata:    expandServiceMetadata(d),
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		tflog.Trace(ctx, "updated a service")
+	}
+
+	if d.HasChange("resource") {
+		o, n := d.GetChange("resource")
+		inlineResources, err := updateInlineResources(ctx, client, slug, o.([]interface{}), n.([]interface{}), d.Timeout(schema.TimeoutUpdate))
+		d.Set("resource", inlineResources)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return serviceRead(ctx, d, meta)
+}
+
 func serviceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*clarity.Client)
 	slug := d.Id()
+
+	if d.Get("force_destroy").(bool) {
+		service, err := client.LoadService(slug)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if service == nil {
+			return diag.Errorf("Service slug not found.")
+		}
+
+		err = deleteServiceCascade(ctx, client, *service, d.Timeout(schema.TimeoutDelete))
+		return diag.FromErr(err)
+	}
+
+	// Inline resources are owned by the service and removed with it.
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutDelete))
+	for _, r := range d.Get("resource").([]interface{}) {
+		resourceSlug := r.(map[string]interface{})["slug"].(string)
+		if resourceSlug == "" {
+			continue
+		}
+		if err := deleteResourceWhenIdle(ctx, client, slug, resourceSlug, time.Until(deadline)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	err := client.DeleteService(slug)
 	return diag.FromErr(err)
 }
+
+func serviceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := planIdempotencyKey(d); err != nil {
+		return err
+	}
+
+	if d.Id() != "" && d.HasChange("type") {
+		if err := validateServiceTypeChange(meta.(*clarity.Client), d); err != nil {
+			return err
+		}
+	}
+
+	return validateInlineResources(d)
+}
+
+// validateServiceTypeChange checks the resources already attached to the
+// service are supported by its new type.
+func validateServiceTypeChange(api *clarity.Client, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+
+	service, err := api.LoadService(d.Id())
+	if err != nil {
+		return fmt.Errorf("loading service '%s' for validation: %v", d.Id(), err)
+	}
+	if service == nil {
+		return nil
+	}
+
+	service.ServiceType = d.Get("type").(string)
+	for _, r := range service.Resources {
+		internal, err := api.ReadResource(service.Slug, r.Slug)
+		if err != nil {
+			return fmt.Errorf("loading resource '%s' for validation: %v", r.Slug, err)
+		}
+		// Types this client does not recognise are left to the server.
+		if !internal.Data.IsUnknown() && !service.SupportsResourceType(internal.Data.Type) {
+			return fmt.Errorf("Service '%s' can not change to type '%s', its resource '%s' of type '%s' is not supported", service.Slug, service.ServiceType, r.Slug, internal.Data.Type)
+		}
+	}
+
+	return nil
+}
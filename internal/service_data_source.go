@@ -2,7 +2,6 @@ package internal
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -22,6 +21,44 @@ func serviceDatasource() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"type": {
+				Description: "The type of service.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"resources": serviceResourcesSchema(),
+			"description": {
+				Description: "A description of the service.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"owner_team": {
+				Description: "The team owning the service.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"repository_url": {
+				Description: "URL of the source repository for the service.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"links": {
+				Description: "Named links for the service, such as a runbook or dashboard.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -35,27 +72,19 @@ func serviceDatasourceRead(ctx context.Context, d *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 
-	matches := 0
-	for _, r := range rsp.Services {
-		if r.Name == name {
-			matches++
-		}
-	}
-	if matches == 0 {
-		return diag.FromErr(fmt.Errorf("No matching service found with the name '%s'", name))
-	}
-
-	if matches > 1 {
-		return diag.FromErr(fmt.Errorf("Found multiple service with the name '%s'", name))
+	r, err := findServiceByName(rsp.Services, name)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	for _, r := range rsp.Services {
-		if r.Name == name {
-			d.Set("slug", r.Slug)
-			d.SetId(r.Slug)
-			return nil
-		}
-	}
+	d.Set("slug", r.Slug)
+	d.Set("type", r.ServiceType)
+	d.Set("resources", flattenServiceResources(r.Resources))
+	d.Set("description", r.Description)
+	d.Set("owner_team", r.OwnerTeam)
+	d.Set("repository_url", r.RepositoryURL)
+	d.Set("links", flattenServiceLinks(r.Links))
+	d.SetId(r.Slug)
 
 	return nil
 }
@@ -19,6 +19,8 @@ func TestAccServiceDataSource(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(dataSourceName, "slug", regexp.MustCompile("^terraform-test")),
 					resource.TestCheckResourceAttr(dataSourceName, "name", "terraform-test"),
+					resource.TestCheckResourceAttr(dataSourceName, "type", "function"),
+					resource.TestCheckResourceAttr(dataSourceName, "resources.#", "0"),
 				),
 			},
 		},
@@ -0,0 +1,310 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Resources may be declared inline on a service, these are sent in the single
+// service create request and are afterwards managed individually by name.
+func inlineResourceSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Resources created together with the service. Changing the name or Lambda alias of a resource updates it in place, changing its provider or function replaces it.",
+		Type:        schema.TypeList,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Description:  "A name for the resource.",
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringLenBetween(0, 128),
+				},
+				"provider_slug": {
+					Description: "Provider slug.",
+					Type:        schema.TypeString,
+					Required:    true,
+				},
+				"lambda": {
+					Description: "AWS Lambda import configuration.",
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					MinItems:    1,
+					Required:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"function_name": {
+								Description:  "AWS Lambda function name",
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringLenBetween(0, 255),
+							},
+							"alias": {
+								Description: "AWS Lambda alias",
+								Type:        schema.TypeString,
+								Optional:    true,
+								Default:     "clarity",
+							},
+						},
+					},
+				},
+				"slug": {
+					Description: "A slug for this resource.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func expandInlineResource(in interface{}) clarity.CreateResourceRequest {
+	m := in.(map[string]interface{})
+	lambda := m["lambda"].([]interface{})[0].(map[string]interface{})
+
+	return clarity.CreateResourceRequest{
+		Name:          m["name"].(string),
+		Provider:      m["provider_slug"].(string),
+		RequestType:   clarity.RequestTypeImport,
+		Configuration: expandLambda(lambda),
+	}
+}
+
+func expandInlineResources(in []interface{}) []clarity.CreateResourceRequest {
+	out := make([]clarity.CreateResourceRequest, 0, len(in))
+	for _, r := range in {
+		out = append(out, expandInlineResource(r))
+	}
+	return out
+}
+
+// inlineResourcesByName indexes the inline resource blocks by name.
+func inlineResourcesByName(in []interface{}) map[string]map[string]interface{} {
+	out := make(map[string]map[string]interface{}, len(in))
+	for _, r := range in {
+		m := r.(map[string]interface{})
+		out[m["name"].(string)] = m
+	}
+	return out
+}
+
+// withInlineSlugs copies the slugs of the service's resources onto the inline
+// resource blocks of the same name.
+func withInlineSlugs(in []interface{}, resources []clarity.Resource) []interface{} {
+	slugs := make(map[string]string, len(resources))
+	for _, r := range resources {
+		slugs[r.Name] = r.Slug
+	}
+
+	out := make([]interface{}, 0, len(in))
+	for _, r := range in {
+		m := r.(map[string]interface{})
+		if slug, ok := slugs[m["name"].(string)]; ok {
+			m["slug"] = slug
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// readInlineResources refreshes the inline resources tracked in state, dropping
+// any that no longer exist.
+func readInlineResources(api *clarity.Client, serviceSlug string, in []interface{}) ([]interface{}, error) {
+	out := make([]interface{}, 0, len(in))
+	for _, r := range in {
+		m := r.(map[string]interface{})
+		slug, _ := m["slug"].(string)
+		if slug == "" {
+			continue
+		}
+
+		internal, err := api.ReadResource(serviceSlug, slug)
+		if err != nil {
+			if errors.Is(err, clarity.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		lambda := []interface{}{}
+		if internal.Data.LambdaConfiguration != nil {
+			lambda = append(lambda, flattenLambda(internal.Data.LambdaConfiguration))
+		}
+
+		out = append(out, map[string]interface{}{
+			"name":          internal.Name,
+			"provider_slug": internal.Provider,
+			"lambda":        lambda,
+			"slug":          internal.Slug,
+		})
+	}
+	return out, nil
+}
+
+// inlineResourcePartners pairs each previous inline resource block with the
+// block it becomes. Blocks are matched by name, a block whose name no longer
+// appears is matched with the block at the same position if that one is new,
+// i.e. it was renamed.
+func inlineResourcePartners(o []interface{}, n []interface{}) map[int]map[string]interface{} {
+	previous := inlineResourcesByName(o)
+	next := inlineResourcesByName(n)
+
+	out := make(map[int]map[string]interface{}, len(o))
+	for i, r := range o {
+		name := r.(map[string]interface{})["name"].(string)
+		if want, ok := next[name]; ok {
+			out[i] = want
+			continue
+		}
+		if i < len(n) {
+			want := n[i].(map[string]interface{})
+			if _, ok := previous[want["name"].(string)]; !ok {
+				out[i] = want
+			}
+		}
+	}
+	return out
+}
+
+// updatableInPlace reports whether an inline resource can be changed into the
+// wanted one with an update, only the name and Lambda alias may change, as on
+// clarity_resource.
+func updatableInPlace(have clarity.CreateResourceRequest, want clarity.CreateResourceRequest) bool {
+	return have.Provider == want.Provider &&
+		have.Configuration.LambdaConfiguration != nil &&
+		want.Configuration.LambdaConfiguration != nil &&
+		have.Configuration.LambdaConfiguration.Name == want.Configuration.LambdaConfiguration.Name
+}
+
+// updateInlineResources deletes removed inline resources, updates renamed or
+// changed ones in place where possible, replaces the rest and creates added
+// ones, in configuration order. On failure the inline resources as they exist
+// on the server are returned together with the error.
+func updateInlineResources(ctx context.Context, api *clarity.Client, serviceSlug string, o []interface{}, n []interface{}, timeout time.Duration) ([]interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	next := inlineResourcesByName(n)
+	partners := inlineResourcePartners(o, n)
+
+	// current tracks the blocks matching the resources on the server.
+	current := inlineResourcesByName(o)
+	render := func() []interface{} {
+		out := make([]interface{}, 0, len(current))
+		for _, r := range n {
+			if m, ok := current[r.(map[string]interface{})["name"].(string)]; ok {
+				out = append(out, m)
+			}
+		}
+		for _, r := range o {
+			name := r.(map[string]interface{})["name"].(string)
+			if _, ok := next[name]; !ok {
+				if m, ok := current[name]; ok {
+					out = append(out, m)
+				}
+			}
+		}
+		return out
+	}
+
+	// Deletes go first, freeing their names for renames and creates.
+	for i, r := range o {
+		m := r.(map[string]interface{})
+		name := m["name"].(string)
+
+		if want, ok := partners[i]; ok && updatableInPlace(expandInlineResource(m), expandInlineResource(want)) {
+			continue
+		}
+
+		tflog.Trace(ctx, fmt.Sprintf("deleting inline resource: %s", name))
+		if err := deleteResourceWhenIdle(ctx, api, serviceSlug, m["slug"].(string), time.Until(deadline)); err != nil {
+			return render(), fmt.Errorf("deleting resource '%s': %w", name, err)
+		}
+		delete(current, name)
+		delete(partners, i)
+	}
+
+	for i, r := range o {
+		want, ok := partners[i]
+		if !ok {
+			continue
+		}
+		m := r.(map[string]interface{})
+		name := m["name"].(string)
+		req := expandInlineResource(want)
+
+		if !reflect.DeepEqual(expandInlineResource(m), req) {
+			tflog.Trace(ctx, fmt.Sprintf("updating inline resource: %s", name))
+			_, err := api.UpdateResource(serviceSlug, m["slug"].(string), clarity.UpdateResourceRequest{
+				Name:          req.Name,
+				Configuration: req.Configuration,
+			})
+			if err != nil {
+				return render(), fmt.Errorf("updating resource '%s': %w", name, err)
+			}
+		}
+
+		want["slug"] = m["slug"]
+		delete(current, name)
+		current[req.Name] = want
+	}
+
+	for _, r := range n {
+		m := r.(map[string]interface{})
+		name := m["name"].(string)
+		if _, ok := current[name]; ok {
+			continue
+		}
+
+		tflog.Trace(ctx, fmt.Sprintf("creating inline resource: %s", name))
+		req := expandInlineResource(m)
+		key := clarity.NewIdempotencyKey()
+		var internal *clarity.InternalResource
+		err := createWithRetry(ctx, time.Until(deadline), func() (err error) {
+			internal, err = api.CreateResource(serviceSlug, req, key)
+			return err
+		})
+		if err != nil {
+			return render(), fmt.Errorf("creating resource '%s': %w", name, err)
+		}
+		m["slug"] = internal.Slug
+		current[name] = m
+	}
+
+	return render(), nil
+}
+
+// validateInlineResources rejects duplicate names and resource types the
+// service type does not support.
+func validateInlineResources(d *schema.ResourceDiff) error {
+	service := clarity.Service{
+		ServiceType: d.Get("type").(string),
+	}
+
+	resources := d.Get("resource").([]interface{})
+	if len(resources) > 0 && !service.SupportsResourceType(clarity.ResourceTypeLambda) {
+		return fmt.Errorf("Service of type '%s' does not support '%s' resources", service.ServiceType, clarity.ResourceTypeLambda)
+	}
+
+	names := make(map[string]bool)
+	for _, r := range resources {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name := m["name"].(string)
+		if names[name] {
+			return fmt.Errorf("Resource names must be unique, '%s' is declared more than once", name)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+)
+
+func inlineResource(name string, function string, slug string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          name,
+		"provider_slug": "provider",
+		"lambda": []interface{}{
+			map[string]interface{}{"function_name": function, "alias": "clarity"},
+		},
+		"slug": slug,
+	}
+}
+
+func TestUpdateInlineResourcesPartialFailure(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": "invalid"}`))
+		}
+	}))
+	defer server.Close()
+
+	api := &clarity.Client{Host: server.URL, Client: server.Client()}
+
+	o := []interface{}{
+		inlineResource("a", "a", "a-slug"),
+		inlineResource("b", "b", "b-slug"),
+		inlineResource("d", "d", "d-slug"),
+	}
+	n := []interface{}{
+		inlineResource("c", "c", ""),
+		inlineResource("a", "a", ""),
+		inlineResource("b", "b-changed", ""),
+	}
+
+	out, err := updateInlineResources(context.Background(), api, "service", o, n, time.Minute)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	// b and d were deleted, a is unchanged and c was never created.
+	expected := []interface{}{inlineResource("a", "a", "a-slug")}
+	if !reflect.DeepEqual(expected, out) {
+		t.Errorf("updateInlineResources() = %v, expected %v", out, expected)
+	}
+
+	expectedRequests := []string{
+		"DELETE /service/service/resource/b-slug",
+		"DELETE /service/service/resource/d-slug",
+		"POST /service/service/resource",
+	}
+	if !reflect.DeepEqual(expectedRequests, requests) {
+		t.Errorf("requests = %v, expected %v", requests, expectedRequests)
+	}
+}
+
+func TestUpdateInlineResourcesRetriesCreate(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(clarity.IdempotencyKey))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name": "a", "slug": "a-slug"}`))
+	}))
+	defer server.Close()
+
+	api := &clarity.Client{Host: server.URL, Client: server.Client()}
+
+	out, err := updateInlineResources(context.Background(), api, "service", nil, []interface{}{inlineResource("a", "a", "")}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []interface{}{inlineResource("a", "a", "a-slug")}; !reflect.DeepEqual(expected, out) {
+		t.Errorf("updateInlineResources() = %v, expected %v", out, expected)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("expected both attempts to send the same key, got %v", keys)
+	}
+}
+
+func TestUpdateInlineResourcesInPlace(t *testing.T) {
+	var requests []string
+	var updates []clarity.UpdateResourceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			var req clarity.UpdateResourceRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			updates = append(updates, req)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	api := &clarity.Client{Host: server.URL, Client: server.Client()}
+
+	aliased := inlineResource("b", "b", "")
+	aliased["lambda"] = []interface{}{
+		map[string]interface{}{"function_name": "b", "alias": "live"},
+	}
+
+	o := []interface{}{
+		inlineResource("a", "a", "a-slug"),
+		inlineResource("b", "b", "b-slug"),
+	}
+	n := []interface{}{
+		inlineResource("a-renamed", "a", ""),
+		aliased,
+	}
+
+	out, err := updateInlineResources(context.Background(), api, "service", o, n, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedRequests := []string{
+		"POST /service/service/resource/a-slug",
+		"POST /service/service/resource/b-slug",
+	}
+	if !reflect.DeepEqual(expectedRequests, requests) {
+		t.Errorf("requests = %v, expected %v", requests, expectedRequests)
+	}
+	if len(updates) != 2 || updates[0].Name != "a-renamed" || updates[1].Configuration.LambdaConfiguration.Alias != "live" {
+		t.Errorf("unexpected updates %+v", updates)
+	}
+
+	if len(out) != 2 || out[0].(map[string]interface{})["slug"] != "a-slug" || out[1].(map[string]interface{})["slug"] != "b-slug" {
+		t.Errorf("expected the slugs to be kept, got %v", out)
+	}
+}
@@ -1,13 +1,20 @@
 package internal
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"testing"
 
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccService(t *testing.T) {
+	var id string
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: providerFactories,
@@ -18,18 +25,101 @@ func TestAccService(t *testing.T) {
 					resource.TestMatchResourceAttr(
 						"clarity_provider.test", "slug", regexp.MustCompile("^terraform-test")),
 					resource.TestCheckResourceAttr("clarity_service.test", "name", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_service.test", "type", "function"),
 					resource.TestMatchResourceAttr(
 						"clarity_service.test", "slug", regexp.MustCompile("^terraform-test")),
+					testAccStoreResourceID("clarity_service.test", &id),
 				),
 			},
 			{
 				ResourceName:            "clarity_service.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"force_destroy"},
+				ImportStateVerifyIgnore: []string{"force_destroy", "idempotency_key"},
 			},
+			{
+				ResourceName:            "clarity_service.test",
+				ImportState:             true,
+				ImportStateId:           "name:terraform-test",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"idempotency_key"},
+			},
+			{
+				Config: testAccProvider() + testAccServiceRenamed,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_service.test", "name", "terraform-test-renamed"),
+					resource.TestMatchResourceAttr(
+						"clarity_service.test", "slug", regexp.MustCompile("^terraform-test")),
+				),
+			},
+			{
+				Config: testAccProvider() + testAccServiceMetadata,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_service.test", "description", "Terraform test service"),
+					resource.TestCheckResourceAttr("clarity_service.test", "owner_team", "platform"),
+					resource.TestCheckResourceAttr("clarity_service.test", "repository_url", "https://github.com/clarity-st/terraform-provider-clarity"),
+					resource.TestCheckResourceAttr("clarity_service.test", "links.#", "1"),
+					resource.TestCheckResourceAttr("clarity_service.test", "links.0.name", "runbook"),
+					resource.TestCheckResourceAttr("clarity_service.test", "links.0.url", "https://docs.clarity.st"),
+				),
+			},
+			{
+				Config: testAccProvider() + testAccServiceStaticSite,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_service.test", "type", "static-site"),
+					testAccCheckResourceID("clarity_service.test", &id, true),
+				),
+			},
+		},
+	})
+}
+
+func TestServiceTypeChange(t *testing.T) {
+	resourceType := "aws"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services":
+			w.Write([]byte(`{"services": [{"name": "service", "slug": "service", "type": "function", "repository_provider": {"slug": "provider"}, "resources": [{"name": "fn", "slug": "fn", "provider": "provider"}]}]}`))
+		case "/service/service/resource/fn":
+			fmt.Fprintf(w, `{"name": "fn", "slug": "fn", "provider": "provider", "data": {"type": %q, "configuration": {"name": "fn"}}}`, resourceType)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	api := &clarity.Client{Host: server.URL, Client: server.Client()}
+
+	state := &terraform.InstanceState{
+		ID: "service",
+		Attributes: map[string]string{
+			"id":            "service",
+			"provider_slug": "provider",
+			"name":          "service",
+			"type":          "function",
+			"slug":          "service",
 		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"provider_slug": "provider",
+		"name":          "service",
+		"type":          "container",
 	})
+
+	_, err := serviceResource().SimpleDiff(context.Background(), state, config, api)
+	if err == nil || err.Error() != "Service 'service' can not change to type 'container', its resource 'fn' of type 'lambda' is not supported" {
+		t.Errorf("expected an unsupported resource error, got %v", err)
+	}
+
+	// Resource types this client does not recognise are left to the server,
+	// the type changes in place.
+	resourceType = "future"
+	diff, err := serviceResource().SimpleDiff(context.Background(), state, config, api)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.RequiresNew() || diff.Attributes["type"] == nil || diff.Attributes["type"].New != "container" {
+		t.Errorf("expected the type to change in place, got %v", diff)
+	}
 }
 
 const testAccService = `
@@ -38,3 +128,70 @@ resource "clarity_service" "test" {
   name = "terraform-test"
 }
 `
+
+const testAccServiceStaticSite = `
+resource "clarity_service" "test" {
+  provider_slug = clarity_provider.test.slug
+  name = "terraform-test-renamed"
+  type = "static-site"
+}
+`
+
+const testAccServiceRenamed = `
+resource "clarity_service" "test" {
+  provider_slug = clarity_provider.test.slug
+  name = "terraform-test-renamed"
+}
+`
+
+const testAccServiceMetadata = `
+resource "clarity_service" "test" {
+  provider_slug = clarity_provider.test.slug
+  name = "terraform-test-renamed"
+
+  description = "Terraform test service"
+  owner_team = "platform"
+  repository_url = "https://github.com/clarity-st/terraform-provider-clarity"
+
+  links {
+    name = "runbook"
+    url = "https://docs.clarity.st"
+  }
+}
+`
+
+func TestAccServiceInlineResources(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProvider() + testAccServiceInlineResource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clarity_service.test", "resource.#", "1"),
+					resource.TestCheckResourceAttr("clarity_service.test", "resource.0.name", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_service.test", "resource.0.lambda.0.function_name", "terraform-test"),
+					resource.TestCheckResourceAttr("clarity_service.test", "resource.0.lambda.0.alias", "clarity"),
+					resource.TestMatchResourceAttr(
+						"clarity_service.test", "resource.0.slug", regexp.MustCompile("^terraform-test")),
+				),
+			},
+		},
+	})
+}
+
+const testAccServiceInlineResource = `
+resource "clarity_service" "test" {
+  provider_slug = clarity_provider.test.slug
+  name = "terraform-test"
+
+  resource {
+    name = "terraform-test"
+    provider_slug = clarity_provider.test.slug
+
+    lambda {
+      function_name = "terraform-test"
+    }
+  }
+}
+`
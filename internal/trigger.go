@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	triggerManual   = "manual"
+	triggerAlways   = "always"
+	triggerSchedule = "schedule"
+	triggerBranch   = "branch"
+	triggerTag      = "tag"
+)
+
+var triggerTypes = []string{
+	triggerManual,
+	triggerAlways,
+	triggerSchedule,
+	triggerBranch,
+	triggerTag,
+}
+
+func triggerSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Ordered deployment trigger rules",
+		Type:        schema.TypeList,
+		MinItems:    1,
+		Required:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Description:  "The trigger type, one of `manual`, `always`, `schedule`, `branch` or `tag`.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice(triggerTypes, false),
+				},
+				"pattern": {
+					Description:  "Branch or tag name pattern, e.g. `main` or `v*`. Required for `branch` and `tag` triggers.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringLenBetween(1, 255),
+				},
+				"manual_user_interface": {
+					Description: "Enable manual deployment lock, controlled via the user interface",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Deprecated:  "Use `type = \"manual\"` or `type = \"always\"` instead.",
+				},
+				"schedule": scheduleSchema(),
+			},
+		},
+	}
+}
+
+// expandTrigger maps a trigger block onto a rule. Blocks without a type use
+// the legacy form, where manual_user_interface selects between the manual
+// and always rules.
+func expandTrigger(m map[string]interface{}) clarity.DeploymentRule {
+	schedule := expandSchedule(m["schedule"].([]interface{}))
+
+	triggerType := m["type"].(string)
+	if triggerType == "" {
+		switch {
+		case schedule != nil:
+			triggerType = triggerSchedule
+		case m["manual_user_interface"].(bool):
+			triggerType = triggerManual
+		default:
+			triggerType = triggerAlways
+		}
+	}
+
+	switch triggerType {
+	case triggerManual:
+		return clarity.DeploymentRule{Type: clarity.TriggerTypeEvent, Event: clarity.EventManual}
+	case triggerSchedule:
+		return clarity.DeploymentRule{Type: clarity.TriggerTypeSchedule, Schedule: schedule}
+	case triggerBranch:
+		return clarity.DeploymentRule{Type: clarity.TriggerTypeEvent, Event: clarity.EventBranch, Pattern: m["pattern"].(string)}
+	case triggerTag:
+		return clarity.DeploymentRule{Type: clarity.TriggerTypeEvent, Event: clarity.EventTag, Pattern: m["pattern"].(string)}
+	default:
+		return clarity.DeploymentRule{Type: clarity.TriggerTypeAlways}
+	}
+}
+
+func expandTriggers(in []interface{}) []clarity.DeploymentRule {
+	rules := make([]clarity.DeploymentRule, 0, len(in))
+	for _, t := range in {
+		if t == nil {
+			continue
+		}
+		rules = append(rules, expandTrigger(t.(map[string]interface{})))
+	}
+	return rules
+}
+
+func triggerType(rule clarity.DeploymentRule) string {
+	switch {
+	case rule.Type == clarity.TriggerTypeSchedule:
+		return triggerSchedule
+	case rule.IsManual():
+		return triggerManual
+	case rule.Type == clarity.TriggerTypeEvent && rule.Event == clarity.EventBranch:
+		return triggerBranch
+	case rule.Type == clarity.TriggerTypeEvent && rule.Event == clarity.EventTag:
+		return triggerTag
+	default:
+		return triggerAlways
+	}
+}
+
+// flattenTriggers maps rules onto trigger blocks. Blocks previously in state
+// without a type keep the legacy manual_user_interface form so existing
+// configurations don't show a difference.
+func flattenTriggers(rules []clarity.DeploymentRule, prior []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(rules))
+	for i, rule := range rules {
+		legacy := false
+		if i < len(prior) && prior[i] != nil {
+			legacy = prior[i].(map[string]interface{})["type"].(string) == ""
+		}
+
+		m := map[string]interface{}{
+			"type":                  triggerType(rule),
+			"pattern":               rule.Pattern,
+			"manual_user_interface": false,
+			"schedule":              flattenSchedule(rule.Schedule),
+		}
+		if legacy {
+			m["type"] = ""
+			m["manual_user_interface"] = rule.IsManual()
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// validateTriggers checks each trigger block sets only the options relevant
+// to its type.
+func validateTriggers(in []interface{}) error {
+	for i, t := range in {
+		if t == nil {
+			continue
+		}
+		m := t.(map[string]interface{})
+
+		triggerType := m["type"].(string)
+		manual := m["manual_user_interface"].(bool)
+		pattern := m["pattern"].(string)
+		schedule := expandSchedule(m["schedule"].([]interface{}))
+
+		if triggerType != "" && manual {
+			return fmt.Errorf("deployment trigger %d: manual_user_interface can not be combined with type, use type = \"manual\"", i+1)
+		}
+		if triggerType == "" && schedule != nil && manual {
+			return fmt.Errorf("deployment trigger %d: manual_user_interface and schedule can not be combined", i+1)
+		}
+
+		isSchedule := triggerType == triggerSchedule || (triggerType == "" && schedule != nil)
+		if isSchedule && schedule == nil {
+			return fmt.Errorf("deployment trigger %d: a schedule block is required for schedule triggers", i+1)
+		}
+		if !isSchedule && schedule != nil {
+			return fmt.Errorf("deployment trigger %d: a schedule block is only valid for schedule triggers", i+1)
+		}
+		if err := validateSchedule(schedule); err != nil {
+			return err
+		}
+
+		isEvent := triggerType == triggerBranch || triggerType == triggerTag
+		if isEvent && pattern == "" {
+			return fmt.Errorf("deployment trigger %d: pattern is required for %s triggers", i+1, triggerType)
+		}
+		if !isEvent && pattern != "" {
+			return fmt.Errorf("deployment trigger %d: pattern is only valid for branch and tag triggers", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/clarity-st/terraform-provider-clarity/internal/clarity"
+)
+
+func trigger(triggerType string, manual bool, pattern string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":                  triggerType,
+		"pattern":               pattern,
+		"manual_user_interface": manual,
+		"schedule":              []interface{}{},
+	}
+}
+
+func TestExpandFlattenTriggers(t *testing.T) {
+	in := []interface{}{
+		trigger("branch", false, "main"),
+		trigger("manual", false, ""),
+	}
+	rules := expandTriggers(in)
+	expected := []clarity.DeploymentRule{
+		{Type: clarity.TriggerTypeEvent, Event: clarity.EventBranch, Pattern: "main"},
+		{Type: clarity.TriggerTypeEvent, Event: clarity.EventManual},
+	}
+	if !reflect.DeepEqual(expected, rules) {
+		t.Fatalf("expandTriggers() = %v, expected %v", rules, expected)
+	}
+	if out := flattenTriggers(rules, in); !reflect.DeepEqual(in, out) {
+		t.Fatalf("flattenTriggers() = %v, expected %v", out, in)
+	}
+
+	// Legacy blocks keep their form when read back.
+	legacy := []interface{}{trigger("", true, "")}
+	rules = expandTriggers(legacy)
+	if !reflect.DeepEqual([]clarity.DeploymentRule{{Type: clarity.TriggerTypeEvent, Event: clarity.EventManual}}, rules) {
+		t.Fatalf("unexpected legacy rules %v", rules)
+	}
+	if out := flattenTriggers(rules, legacy); !reflect.DeepEqual(legacy, out) {
+		t.Fatalf("flattenTriggers() = %v, expected %v", out, legacy)
+	}
+}
+
+func TestValidateTriggers(t *testing.T) {
+	valid := []interface{}{
+		trigger("", false, ""),
+		trigger("always", false, ""),
+		trigger("tag", false, "v*"),
+	}
+	if err := validateTriggers(valid); err != nil {
+		t.Errorf("expected triggers to be valid: %v", err)
+	}
+
+	for _, m := range []map[string]interface{}{
+		trigger("manual", true, ""),
+		trigger("branch", false, ""),
+		trigger("always", false, "main"),
+		trigger("schedule", false, ""),
+	} {
+		if err := validateTriggers([]interface{}{m}); err == nil {
+			t.Errorf("expected %v to be invalid", m)
+		}
+	}
+}